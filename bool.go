@@ -0,0 +1,31 @@
+package patreon
+
+import (
+	"encoding/json"
+	"strconv"
+)
+
+// Bool represents a bool that may be serialized by API as JSON boolean, string ("true", "false") or null.
+// It's used for Campaign flags, which are occasionally returned as strings.
+type Bool bool
+
+// UnmarshalJSON implements json.Unmarshaler with string and JSON "null" support
+func (b *Bool) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		*b = false
+		return nil
+	}
+
+	if unquoted, err := strconv.Unquote(s); err == nil {
+		s = unquoted
+	}
+
+	var value bool
+	if err := json.Unmarshal([]byte(s), &value); err != nil {
+		return err
+	}
+
+	*b = Bool(value)
+	return nil
+}
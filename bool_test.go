@@ -0,0 +1,35 @@
+package patreon
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBool_parse(t *testing.T) {
+	for data, expected := range map[string]Bool{
+		`{ "value": true }`:    true,
+		`{ "value": false }`:   false,
+		`{ "value": "true" }`:  true,
+		`{ "value": "false" }`: false,
+		`{ "value": null }`:    false,
+	} {
+		s := &struct {
+			Value Bool `json:"value"`
+		}{}
+
+		err := json.Unmarshal([]byte(data), s)
+		require.NoError(t, err)
+		require.Equal(t, expected, s.Value, data)
+	}
+}
+
+func TestBool_parseInvalid(t *testing.T) {
+	s := &struct {
+		Value Bool `json:"value"`
+	}{}
+
+	err := json.Unmarshal([]byte(`{ "value": "yes" }`), s)
+	require.Error(t, err)
+}
@@ -1,5 +1,7 @@
 package patreon
 
+import "sort"
+
 // CampaignDefaultRelations specifies default includes for Campaign.
 const CampaignDefaultRelations = "rewards,creator,goals"
 
@@ -11,7 +13,7 @@ type Campaign struct {
 	Attributes struct {
 		Summary                       string   `json:"summary"`
 		CreationName                  string   `json:"creation_name"`
-		DisplayPatronGoals            bool     `json:"display_patron_goals"`
+		DisplayPatronGoals            Bool     `json:"display_patron_goals"`
 		PayPerName                    string   `json:"pay_per_name"`
 		OneLiner                      string   `json:"one_liner"`
 		MainVideoEmbed                string   `json:"main_video_embed"`
@@ -21,10 +23,10 @@ type Campaign struct {
 		ThanksVideoURL                string   `json:"thanks_video_url"`
 		ThanksEmbed                   string   `json:"thanks_embed"`
 		ThanksMsg                     string   `json:"thanks_msg"`
-		IsChargedImmediately          bool     `json:"is_charged_immediately"`
-		IsMonthly                     bool     `json:"is_monthly"`
-		IsNsfw                        bool     `json:"is_nsfw"`
-		IsPlural                      bool     `json:"is_plural"`
+		IsChargedImmediately          Bool     `json:"is_charged_immediately"`
+		IsMonthly                     Bool     `json:"is_monthly"`
+		IsNsfw                        Bool     `json:"is_nsfw"`
+		IsPlural                      Bool     `json:"is_plural"`
 		CreatedAt                     NullTime `json:"created_at"`
 		PublishedAt                   NullTime `json:"published_at"`
 		PledgeURL                     string   `json:"pledge_url"`
@@ -43,8 +45,72 @@ type Campaign struct {
 	} `json:"relationships"`
 }
 
+// IsPublished reports whether the campaign has been published (drafts have no publish date).
+func (c *Campaign) IsPublished() bool {
+	return c.Attributes.PublishedAt.Valid
+}
+
+// CoverImageURL returns the campaign's cover image, falling back to the small (thumbnail) image when there is none.
+func (c *Campaign) CoverImageURL() string {
+	if c.Attributes.ImageURL != "" {
+		return c.Attributes.ImageURL
+	}
+
+	return c.Attributes.ImageSmallURL
+}
+
+// RewardIDs returns the IDs of the campaign's rewards (tiers) as listed in relationships.
+// Rewards don't need to be included for their IDs to be available.
+func (c *Campaign) RewardIDs() []string {
+	if c.Relationships.Rewards == nil {
+		return nil
+	}
+
+	return relationshipIDs(c.Relationships.Rewards.Data)
+}
+
+// GoalIDs returns the IDs of the campaign's goals as listed in relationships.
+func (c *Campaign) GoalIDs() []string {
+	if c.Relationships.Goals == nil {
+		return nil
+	}
+
+	return relationshipIDs(c.Relationships.Goals.Data)
+}
+
 // CampaignResponse wraps Patreon's campaign API response
 type CampaignResponse struct {
 	Data     []Campaign `json:"data"`
 	Included Includes   `json:"included"`
 }
+
+// RewardByID returns the included reward (tier) with the given ID, or nil if it wasn't included.
+func (r *CampaignResponse) RewardByID(id string) *Reward {
+	return r.Included.Reward(id)
+}
+
+// GoalByID returns the included goal with the given ID, or nil if it wasn't included.
+func (r *CampaignResponse) GoalByID(id string) *Goal {
+	return r.Included.Goal(id)
+}
+
+// GoalsSortedByAmount returns the included goals in ascending order of their target amount.
+func (r *CampaignResponse) GoalsSortedByAmount() []*Goal {
+	var goals []*Goal
+	for _, item := range r.Included.Items {
+		if goal, ok := item.(*Goal); ok {
+			goals = append(goals, goal)
+		}
+	}
+
+	sort.Stable(goalsByAmount(goals))
+	return goals
+}
+
+type goalsByAmount []*Goal
+
+func (g goalsByAmount) Len() int      { return len(g) }
+func (g goalsByAmount) Swap(i, j int) { g[i], g[j] = g[j], g[i] }
+func (g goalsByAmount) Less(i, j int) bool {
+	return g[i].Attributes.AmountCents < g[j].Attributes.AmountCents
+}
@@ -1,9 +1,12 @@
 package patreon
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -28,19 +31,21 @@ func TestFetchCampaign(t *testing.T) {
 
 	attrs := resp.Data[0].Attributes
 	require.Equal(t, 8, attrs.CreationCount)
-	require.True(t, attrs.DisplayPatronGoals)
+	require.True(t, bool(attrs.DisplayPatronGoals))
 	require.NotEmpty(t, attrs.ImageSmallURL)
 	require.NotEmpty(t, attrs.ImageURL)
-	require.True(t, attrs.IsChargedImmediately)
-	require.True(t, attrs.IsMonthly)
-	require.True(t, attrs.IsNsfw)
-	require.True(t, attrs.IsPlural)
+	require.True(t, bool(attrs.IsChargedImmediately))
+	require.True(t, bool(attrs.IsMonthly))
+	require.True(t, bool(attrs.IsNsfw))
+	require.True(t, bool(attrs.IsPlural))
 	require.Equal(t, 123121, attrs.PatronCount)
 	require.Equal(t, "month", attrs.PayPerName)
 	require.Equal(t, 12321312, attrs.PledgeSum)
 	require.NotEmpty(t, attrs.Summary)
 	require.NotEmpty(t, attrs.PledgeURL)
-	require.NotEmpty(t, attrs.ThanksMsg)
+	require.Equal(t, "You are awesome!", attrs.ThanksMsg)
+	require.Empty(t, attrs.ThanksVideoURL)
+	require.Empty(t, attrs.ThanksEmbed)
 
 	// Relationships
 
@@ -77,6 +82,158 @@ func TestFetchCampaign(t *testing.T) {
 	require.Equal(t, 1000, goal.Attributes.Amount)
 }
 
+func TestCampaignThanksAttributes(t *testing.T) {
+	campaign := Campaign{}
+	err := json.Unmarshal([]byte(`{
+		"attributes": {
+			"thanks_embed": "<iframe src=\"https://www.youtube.com/embed/abc\"></iframe>",
+			"thanks_msg": "You are awesome!",
+			"thanks_video_url": "https://www.youtube.com/watch?v=abc"
+		},
+		"id": "278915",
+		"type": "campaign"
+	}`), &campaign)
+	require.NoError(t, err)
+
+	attrs := campaign.Attributes
+	require.Equal(t, "You are awesome!", attrs.ThanksMsg)
+	require.Equal(t, "https://www.youtube.com/watch?v=abc", attrs.ThanksVideoURL)
+	require.Equal(t, `<iframe src="https://www.youtube.com/embed/abc"></iframe>`, attrs.ThanksEmbed)
+}
+
+func TestFetchPublishedCampaigns(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/oauth2/api/current_user/campaigns", func(writer http.ResponseWriter, request *http.Request) {
+		fmt.Fprint(writer, `{"data": [
+			{"attributes": {"published_at": null}, "id": "1", "type": "campaign"},
+			{"attributes": {"published_at": "2016-02-02T20:11:19+00:00"}, "id": "2", "type": "campaign"}
+		]}`)
+	})
+
+	resp, err := client.FetchCampaign()
+	require.NoError(t, err)
+	require.Len(t, resp.Data, 2)
+	require.False(t, resp.Data[0].IsPublished())
+	require.True(t, resp.Data[1].IsPublished())
+
+	resp, err = client.FetchPublishedCampaigns()
+	require.NoError(t, err)
+	require.Len(t, resp.Data, 1)
+	require.Equal(t, "2", resp.Data[0].ID)
+}
+
+func TestCachedCampaign(t *testing.T) {
+	setup()
+	defer teardown()
+
+	now := time.Date(2017, 6, 20, 23, 21, 34, 0, time.UTC)
+	client.now = func() time.Time { return now }
+
+	var (
+		mu       sync.Mutex
+		requests int
+	)
+
+	mux.HandleFunc("/oauth2/api/current_user/campaigns", func(writer http.ResponseWriter, request *http.Request) {
+		mu.Lock()
+		requests++
+		mu.Unlock()
+
+		require.Equal(t, "creator,goals,rewards", request.URL.Query().Get("include"))
+		fmt.Fprint(writer, fetchCampaignResp)
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := client.CachedCampaign(time.Minute)
+			require.NoError(t, err)
+			require.Equal(t, "278915", resp.Data[0].ID)
+		}()
+	}
+	wg.Wait()
+	require.Equal(t, 1, requests)
+
+	now = now.Add(59 * time.Second)
+	_, err := client.CachedCampaign(time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, 1, requests)
+
+	now = now.Add(time.Second)
+	_, err = client.CachedCampaign(time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, 2, requests)
+}
+
+func TestCampaignIncludesByID(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/oauth2/api/current_user/campaigns", func(writer http.ResponseWriter, request *http.Request) {
+		fmt.Fprint(writer, fetchCampaignResp)
+	})
+
+	resp, err := client.FetchCampaign()
+	require.NoError(t, err)
+
+	reward := resp.RewardByID("12312312")
+	require.NotNil(t, reward)
+	require.Equal(t, 100, reward.Attributes.Amount)
+
+	goal := resp.GoalByID("2131231")
+	require.NotNil(t, goal)
+	require.Equal(t, 1000, goal.Attributes.Amount)
+
+	require.Nil(t, resp.RewardByID("2131231"))
+	require.Nil(t, resp.GoalByID("12312312"))
+}
+
+func TestCampaignRelationshipIDs(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/oauth2/api/current_user/campaigns", func(writer http.ResponseWriter, request *http.Request) {
+		fmt.Fprint(writer, fetchCampaignResp)
+	})
+
+	resp, err := client.FetchCampaign()
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"-1", "12312312"}, resp.Data[0].RewardIDs())
+	require.Equal(t, []string{"2131231"}, resp.Data[0].GoalIDs())
+
+	campaign := Campaign{}
+	require.Empty(t, campaign.RewardIDs())
+	require.Empty(t, campaign.GoalIDs())
+}
+
+func TestCampaignGoalsSortedByAmount(t *testing.T) {
+	resp := CampaignResponse{}
+	err := json.Unmarshal([]byte(campaignGoalsResp), &resp)
+	require.NoError(t, err)
+
+	goals := resp.GoalsSortedByAmount()
+	require.Len(t, goals, 3)
+	require.Equal(t, "2", goals[0].ID)
+	require.Equal(t, "3", goals[1].ID)
+	require.Equal(t, "1", goals[2].ID)
+}
+
+func TestCampaignCoverImageURL(t *testing.T) {
+	campaign := Campaign{}
+	require.Empty(t, campaign.CoverImageURL())
+
+	campaign.Attributes.ImageSmallURL = "https://example.com/small.png"
+	require.Equal(t, "https://example.com/small.png", campaign.CoverImageURL())
+
+	campaign.Attributes.ImageURL = "https://example.com/cover.png"
+	require.Equal(t, "https://example.com/cover.png", campaign.CoverImageURL())
+}
+
 const fetchCampaignResp = `
 {
     "data": [
@@ -92,7 +249,7 @@ const fetchCampaignResp = `
                 "image_url": "https://c10.patreon.com/3/eyJ3IjoxOTIwfQ%3D%3D/patreon-user/AS2N2NrZauWDuhVcuua87P7QtSOdCtPWiazP99SpvJWWHn8d4GvZI56AHqTn94g2_large_2.png?token-time=2145916800&token-hash=4KxOxPVCtGwPskLYr8BZGyZW94VwAKbD7j9RDHcvf0E%3D",
                 "is_charged_immediately": true,
                 "is_monthly": true,
-                "is_nsfw": true,
+                "is_nsfw": "true",
                 "is_plural": true,
                 "main_video_embed": "",
                 "main_video_url": "",
@@ -127,6 +284,26 @@ const fetchCampaignResp = `
                     "links": {
                         "related": "https://www.patreon.com/api/user/2343242423"
                     }
+                },
+                "goals": {
+                    "data": [
+                        {
+                            "id": "2131231",
+                            "type": "goal"
+                        }
+                    ]
+                },
+                "rewards": {
+                    "data": [
+                        {
+                            "id": "-1",
+                            "type": "reward"
+                        },
+                        {
+                            "id": "12312312",
+                            "type": "reward"
+                        }
+                    ]
                 }
             }
         }
@@ -158,3 +335,15 @@ const fetchCampaignResp = `
     ]
 }
 `
+
+const campaignGoalsResp = `
+{
+    "data": [],
+    "included": [
+        {"attributes": {"amount_cents": 60000}, "id": "1", "type": "goal"},
+        {"attributes": {"amount": 100}, "id": "100", "type": "reward"},
+        {"attributes": {"amount_cents": 20000}, "id": "2", "type": "goal"},
+        {"attributes": {"amount_cents": 40000}, "id": "3", "type": "goal"}
+    ]
+}
+`
@@ -0,0 +1,11 @@
+//go:build go1.7
+// +build go1.7
+
+package patreon
+
+import "net/http"
+
+// requestDone returns the channel closed when the request's context is canceled or its deadline is exceeded.
+func requestDone(req *http.Request) <-chan struct{} {
+	return req.Context().Done()
+}
@@ -0,0 +1,11 @@
+//go:build !go1.7
+// +build !go1.7
+
+package patreon
+
+import "net/http"
+
+// requestDone returns nil, as requests have no context prior go1.7 (receiving from nil channel blocks forever).
+func requestDone(req *http.Request) <-chan struct{} {
+	return nil
+}
@@ -0,0 +1,50 @@
+//go:build go1.7
+// +build go1.7
+
+package patreon
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithMaxInFlightContext(t *testing.T) {
+	client := NewClient(nil, WithMaxInFlight(1))
+	client.inFlight <- struct{}{}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	req, err := http.NewRequest("GET", "http://localhost/", nil)
+	require.NoError(t, err)
+
+	err = client.Do(req.WithContext(ctx), nil)
+	require.Equal(t, ErrRequestCanceled, err)
+}
+
+func TestRetryContext(t *testing.T) {
+	setup()
+	defer teardown()
+
+	client = NewClient(nil, WithRetry(1, 2*time.Second))
+	client.baseURL = server.URL
+
+	mux.HandleFunc("/oauth2/api/current_user", func(writer http.ResponseWriter, request *http.Request) {
+		writer.WriteHeader(statusTooManyRequests)
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	req, err := http.NewRequest("GET", server.URL+"/oauth2/api/current_user", nil)
+	require.NoError(t, err)
+
+	start := time.Now()
+	err = client.Do(req.WithContext(ctx), nil)
+	require.Equal(t, ErrRequestCanceled, err)
+	require.True(t, time.Since(start) < time.Second)
+}
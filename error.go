@@ -1,25 +1,145 @@
 package patreon
 
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ErrEmptyCampaignID is returned when an empty campaign ID is passed to methods requiring one.
+var ErrEmptyCampaignID = errors.New("campaign id is required")
+
+// ErrMaxPagesExceeded is returned when there are more pages than allowed by WithMaxPages.
+var ErrMaxPagesExceeded = errors.New("maximum number of pages exceeded")
+
+// ErrMissingCursor is returned by PledgesIterator when the link to the next page has no cursor,
+// so the next page can't be requested.
+var ErrMissingCursor = errors.New("next page link has no cursor")
+
+// ErrRequestCanceled is returned when a request is canceled (with Cancel channel or its context) while waiting
+// for a slot (see WithMaxInFlight) or for the next attempt (see WithRetry).
+var ErrRequestCanceled = errors.New("request canceled while waiting")
+
 // Error describes error details.
 type Error struct {
-	Code     int    `json:"code"`
-	CodeName string `json:"code_name"`
-	Detail   string `json:"detail"`
-	ID       string `json:"id"`
-	Status   string `json:"status"`
-	Title    string `json:"title"`
+	Code     int         `json:"code"`
+	CodeName string      `json:"code_name"`
+	Detail   string      `json:"detail"`
+	ID       string      `json:"id"`
+	Status   string      `json:"status"`
+	Title    string      `json:"title"`
+	Source   ErrorSource `json:"source"`
+}
+
+// ErrorSource points to the part of the request that caused the error.
+type ErrorSource struct {
+	// Pointer is a JSON Pointer (RFC 6901) to the rejected attribute in the request document, e.g. "/data/attributes/uri".
+	Pointer string `json:"pointer"`
+	// Parameter specifies the query parameter that caused the error.
+	Parameter string `json:"parameter"`
 }
 
 // ErrorResponse is a Patreon error response.
 type ErrorResponse struct {
 	Errors []Error `json:"errors"`
+	// StatusCode is the HTTP status code of the response
+	StatusCode int `json:"-"`
 }
 
 func (e ErrorResponse) Error() string {
 	// In most cases there is only one error
 	if len(e.Errors) > 0 {
-		return e.Errors[0].Detail
+		err := e.Errors[0]
+		if err.Source.Pointer != "" {
+			return err.Detail + " (" + err.Source.Pointer + ")"
+		}
+
+		return err.Detail
 	}
 
 	return "(ERR)"
 }
+
+// APIError is returned when API responds with an error status, but the body is not a Patreon error response
+// (e.g. an HTML page from a proxy or an empty body).
+type APIError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e APIError) Error() string {
+	body := strings.TrimSpace(e.Body)
+	if body == "" {
+		return fmt.Sprintf("unexpected status code %d", e.StatusCode)
+	}
+
+	// Avoid dumping entire pages into error messages
+	const maxLen = 256
+	if len(body) > maxLen {
+		body = body[:maxLen] + "..."
+	}
+
+	return fmt.Sprintf("unexpected status code %d: %s", e.StatusCode, body)
+}
+
+// RequestError is returned when a request fails without a response (such as connection refused or timeout).
+// Elapsed tells how long the request took before failing. RequestError implements net.Error, use Timeout to tell
+// a timeout from other failures or Unwrap to access the transport error.
+type RequestError struct {
+	Err     error
+	Elapsed time.Duration
+}
+
+func (e RequestError) Error() string {
+	return fmt.Sprintf("%v (after %s)", e.Err, e.Elapsed)
+}
+
+// Unwrap returns the underlying transport error (usually *url.Error).
+func (e RequestError) Unwrap() error {
+	return e.Err
+}
+
+// Timeout reports whether the request failed because of a timeout.
+func (e RequestError) Timeout() bool {
+	timeout, ok := e.cause().(interface {
+		Timeout() bool
+	})
+
+	return ok && timeout.Timeout()
+}
+
+// Temporary reports whether the underlying transport error is temporary, so RequestError satisfies net.Error.
+func (e RequestError) Temporary() bool {
+	temporary, ok := e.cause().(interface {
+		Temporary() bool
+	})
+
+	return ok && temporary.Temporary()
+}
+
+// timeoutError is wrapped by RequestError when the timeout set with WithRequestTimeout is exceeded between attempts
+// or while waiting for a slot.
+type timeoutError struct{}
+
+func (timeoutError) Error() string {
+	return "request timeout exceeded"
+}
+
+func (timeoutError) Timeout() bool {
+	return true
+}
+
+func (timeoutError) Temporary() bool {
+	return true
+}
+
+// cause returns the error wrapped by *url.Error, as url.Error implements Timeout and Temporary only since go1.6.
+func (e RequestError) cause() error {
+	if urlErr, ok := e.Err.(*url.Error); ok {
+		return urlErr.Err
+	}
+
+	return e.Err
+}
@@ -23,6 +23,7 @@ func TestErrorResponse(t *testing.T) {
 
 	errResp, ok := err.(ErrorResponse)
 	require.True(t, ok)
+	require.Equal(t, http.StatusForbidden, errResp.StatusCode)
 	require.Equal(t, 1, len(errResp.Errors))
 	require.Equal(t, 1, errResp.Errors[0].Code)
 	require.Equal(t, "Unauthorized", errResp.Errors[0].CodeName)
@@ -32,11 +33,65 @@ func TestErrorResponse(t *testing.T) {
 	require.Equal(t, "The server could not verify that you are authorized to access the URL requested.", errResp.Errors[0].Detail)
 }
 
+func TestAPIError(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/oauth2/api/current_user", func(writer http.ResponseWriter, request *http.Request) {
+		writer.WriteHeader(http.StatusBadGateway)
+		fmt.Fprint(writer, "<html><body>Bad Gateway</body></html>")
+	})
+
+	_, err := client.FetchUser()
+	require.Error(t, err)
+	require.Equal(t, "unexpected status code 502: <html><body>Bad Gateway</body></html>", err.Error())
+
+	apiErr, ok := err.(APIError)
+	require.True(t, ok)
+	require.Equal(t, http.StatusBadGateway, apiErr.StatusCode)
+	require.Equal(t, "<html><body>Bad Gateway</body></html>", apiErr.Body)
+}
+
+func TestAPIErrorEmptyBody(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/oauth2/api/current_user", func(writer http.ResponseWriter, request *http.Request) {
+		writer.WriteHeader(statusTooManyRequests)
+	})
+
+	_, err := client.FetchUser()
+	require.Error(t, err)
+	require.Equal(t, "unexpected status code 429", err.Error())
+
+	apiErr, ok := err.(APIError)
+	require.True(t, ok)
+	require.Equal(t, statusTooManyRequests, apiErr.StatusCode)
+}
+
 func TestDefaultErrorString(t *testing.T) {
 	err := ErrorResponse{}
 	require.Equal(t, "(ERR)", err.Error())
 }
 
+func TestErrorSourcePointer(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/oauth2/api/current_user", func(writer http.ResponseWriter, request *http.Request) {
+		writer.WriteHeader(http.StatusUnprocessableEntity)
+		fmt.Fprint(writer, errorSourceResp)
+	})
+
+	_, err := client.FetchUser()
+	require.Error(t, err)
+	require.Equal(t, "URI must be a valid URL. (/data/attributes/uri)", err.Error())
+
+	errResp, ok := err.(ErrorResponse)
+	require.True(t, ok)
+	require.Equal(t, "/data/attributes/uri", errResp.Errors[0].Source.Pointer)
+}
+
 const errorResp = `
 {
     "errors": [
@@ -51,3 +106,21 @@ const errorResp = `
     ]
 }
 `
+
+const errorSourceResp = `
+{
+    "errors": [
+        {
+            "code": 1,
+            "code_name": "ValidationError",
+            "detail": "URI must be a valid URL.",
+            "id": "c3b6a1f2-3e5c-4a3e-9a52-7e36f8ee4d1b",
+            "source": {
+                "pointer": "/data/attributes/uri"
+            },
+            "status": "422",
+            "title": "Validation error"
+        }
+    ]
+}
+`
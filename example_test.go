@@ -83,7 +83,7 @@ func Example_refreshToken() {
 			AuthURL:  AuthorizationURL,
 			TokenURL: AccessTokenURL,
 		},
-		Scopes: []string{"users", "pledges-to-me", "my-campaign"},
+		Scopes: []string{ScopeUsers, ScopePledgesToMe, ScopeMyCampaign},
 	}
 
 	token := oauth2.Token{
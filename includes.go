@@ -8,6 +8,14 @@ import (
 // Includes wraps 'includes' JSON field to handle objects of different type within an array.
 type Includes struct {
 	Items []interface{}
+	keys  []includeKey
+	index map[includeKey]interface{}
+}
+
+// includeKey identifies an included resource by its type and ID.
+type includeKey struct {
+	Type string
+	ID   string
 }
 
 // UnmarshalJSON deserializes 'includes' field into the appropriate structs depending on the 'type' field.
@@ -20,12 +28,16 @@ func (i *Includes) UnmarshalJSON(b []byte) error {
 
 	count := len(items)
 	i.Items = make([]interface{}, count)
-
-	s := struct {
-		Type string `json:"type"`
-	}{}
+	i.keys = make([]includeKey, count)
+	i.index = make(map[includeKey]interface{}, count)
 
 	for idx, raw := range items {
+		// Declared per item, as json.Unmarshal keeps the fields missing in the input
+		s := struct {
+			Type string `json:"type"`
+			ID   string `json:"id"`
+		}{}
+
 		if err := json.Unmarshal(*raw, &s); err != nil {
 			return err
 		}
@@ -56,8 +68,157 @@ func (i *Includes) UnmarshalJSON(b []byte) error {
 			return err
 		}
 
+		key := includeKey{Type: s.Type, ID: s.ID}
 		i.Items[idx] = obj
+		i.keys[idx] = key
+		i.index[key] = obj
 	}
 
 	return nil
 }
+
+// find returns the included resource of the given type and ID, or nil if it wasn't included.
+func (i *Includes) find(typ, id string) interface{} {
+	return i.index[includeKey{Type: typ, ID: id}]
+}
+
+// User returns the included user with the given ID, or nil if it wasn't included.
+func (i *Includes) User(id string) *User {
+	user, _ := i.find("user", id).(*User)
+	return user
+}
+
+// Reward returns the included reward with the given ID, or nil if it wasn't included.
+func (i *Includes) Reward(id string) *Reward {
+	reward, _ := i.find("reward", id).(*Reward)
+	return reward
+}
+
+// Goal returns the included goal with the given ID, or nil if it wasn't included.
+func (i *Includes) Goal(id string) *Goal {
+	goal, _ := i.find("goal", id).(*Goal)
+	return goal
+}
+
+// Campaign returns the included campaign with the given ID, or nil if it wasn't included.
+func (i *Includes) Campaign(id string) *Campaign {
+	campaign, _ := i.find("campaign", id).(*Campaign)
+	return campaign
+}
+
+// Pledge returns the included pledge with the given ID, or nil if it wasn't included.
+func (i *Includes) Pledge(id string) *Pledge {
+	pledge, _ := i.find("pledge", id).(*Pledge)
+	return pledge
+}
+
+// Card returns the included card with the given ID, or nil if it wasn't included.
+func (i *Includes) Card(id string) *Card {
+	card, _ := i.find("card", id).(*Card)
+	return card
+}
+
+// Address returns the included address with the given ID, or nil if it wasn't included.
+func (i *Includes) Address(id string) *Address {
+	address, _ := i.find("address", id).(*Address)
+	return address
+}
+
+// merge appends the resources of other that aren't already included.
+func (i *Includes) merge(other Includes) {
+	if i.index == nil {
+		i.index = make(map[includeKey]interface{})
+	}
+
+	for idx, key := range other.keys {
+		if _, ok := i.index[key]; ok {
+			continue
+		}
+
+		obj := other.Items[idx]
+		i.Items = append(i.Items, obj)
+		i.keys = append(i.keys, key)
+		i.index[key] = obj
+	}
+}
+
+// UnreferencedIncludesError is returned by requests made with WithAuditIncludes when some included resources are not
+// referenced by any relationship of the response. The response is still decoded.
+type UnreferencedIncludesError struct {
+	Items []Data
+}
+
+func (e UnreferencedIncludesError) Error() string {
+	return fmt.Sprintf("%d included resources are not referenced by any relationship", len(e.Items))
+}
+
+// isAuditError reports whether err only reports the audit result of a successfully decoded response.
+func isAuditError(err error) bool {
+	_, ok := err.(UnreferencedIncludesError)
+	return ok
+}
+
+// auditResource is a minimal representation of a resource used to audit includes
+type auditResource struct {
+	Type          string `json:"type"`
+	ID            string `json:"id"`
+	Relationships map[string]struct {
+		Data json.RawMessage `json:"data"`
+	} `json:"relationships"`
+}
+
+// auditIncludes returns the included resources of the document which are not referenced by any relationship.
+func auditIncludes(body []byte) ([]Data, error) {
+	doc := struct {
+		Data     json.RawMessage `json:"data"`
+		Included []auditResource `json:"included"`
+	}{}
+
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return nil, err
+	}
+
+	resources := doc.Included
+
+	// Primary data is either a single resource or an array of resources
+	var primary []auditResource
+	if len(doc.Data) > 0 {
+		if err := json.Unmarshal(doc.Data, &primary); err != nil {
+			single := auditResource{}
+			if err := json.Unmarshal(doc.Data, &single); err != nil {
+				return nil, err
+			}
+			primary = []auditResource{single}
+		}
+	}
+
+	resources = append(resources, primary...)
+
+	referenced := make(map[includeKey]bool)
+	for _, res := range resources {
+		for _, rel := range res.Relationships {
+			// Relationship data is either null, a single link or an array of links
+			var links []Data
+			if err := json.Unmarshal(rel.Data, &links); err != nil {
+				link := Data{}
+				if err := json.Unmarshal(rel.Data, &link); err != nil {
+					continue
+				}
+				links = []Data{link}
+			}
+
+			for _, link := range links {
+				referenced[includeKey{Type: link.Type, ID: link.ID}] = true
+			}
+		}
+	}
+
+	var orphans []Data
+	for _, res := range doc.Included {
+		if !referenced[includeKey{Type: res.Type, ID: res.ID}] {
+			orphans = append(orphans, Data{ID: res.ID, Type: res.Type})
+		}
+	}
+
+	return orphans, nil
+}
@@ -2,6 +2,8 @@ package patreon
 
 import (
 	"encoding/json"
+	"fmt"
+	"net/http"
 	"testing"
 	"time"
 
@@ -62,6 +64,95 @@ func TestParseIncludes(t *testing.T) {
 	require.Equal(t, "user", card.Relationships.User.Data.Type)
 }
 
+func TestIncludesAccessors(t *testing.T) {
+	includes := Includes{}
+	err := json.Unmarshal([]byte(includesJson), &includes)
+	require.NoError(t, err)
+
+	require.Equal(t, "podsync", includes.User("2822191").Attributes.Vanity)
+	require.Equal(t, 100, includes.Reward("12312312").Attributes.Amount)
+	require.Equal(t, 1000, includes.Goal("2131231").Attributes.Amount)
+	require.Equal(t, "12312321", includes.Campaign("12312321").ID)
+	require.Equal(t, "2321312", includes.Pledge("2321312").ID)
+	require.Equal(t, "bt_12312312", includes.Card("bt_12312312").ID)
+
+	// Lookups are made by both type and ID
+	require.Nil(t, includes.User("12312312"))
+	require.Nil(t, includes.Reward("2822191"))
+	require.Nil(t, includes.Address("1"))
+}
+
+func TestParseIncludesWithoutID(t *testing.T) {
+	includes := Includes{}
+	err := json.Unmarshal([]byte(`[
+		{"attributes": {"full_name": "A"}, "id": "1", "type": "user"},
+		{"attributes": {"full_name": "B"}, "type": "user"}
+	]`), &includes)
+	require.NoError(t, err)
+	require.Len(t, includes.Items, 2)
+
+	require.Equal(t, "A", includes.User("1").Attributes.FullName)
+	require.Equal(t, "B", includes.User("").Attributes.FullName)
+}
+
+func TestIncludesSharedAcrossRelationships(t *testing.T) {
+	resp := &PledgeResponse{}
+	err := json.Unmarshal([]byte(sharedIncludesJson), resp)
+	require.NoError(t, err)
+	require.Len(t, resp.Data, 2)
+	require.Len(t, resp.Included.Items, 2)
+
+	first, second := &resp.Data[0], &resp.Data[1]
+
+	patron := resp.Patron(first)
+	require.NotNil(t, patron)
+	require.Equal(t, "Max", patron.Attributes.FullName)
+	require.True(t, patron == resp.Patron(second))
+
+	webhook := &WebhookPledge{Data: *second, Included: resp.Included}
+	require.True(t, patron == webhook.Patron())
+	require.True(t, resp.Included.Reward("10") == webhook.Reward())
+	require.Equal(t, 500, webhook.Reward().Attributes.Amount)
+}
+
+func TestAuditIncludes(t *testing.T) {
+	orphans, err := auditIncludes([]byte(fetchCampaignResp))
+	require.NoError(t, err)
+	require.Equal(t, []Data{{ID: "2822191", Type: "user"}}, orphans)
+
+	orphans, err = auditIncludes([]byte(pledgeCreateMessage))
+	require.NoError(t, err)
+	require.Len(t, orphans, 0)
+}
+
+func TestAuditIncludesOption(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/oauth2/api/current_user/campaigns", func(writer http.ResponseWriter, request *http.Request) {
+		fmt.Fprint(writer, fetchCampaignResp)
+	})
+
+	resp, err := client.FetchCampaign(WithAuditIncludes())
+	require.Error(t, err)
+	require.Equal(t, "1 included resources are not referenced by any relationship", err.Error())
+
+	auditErr, ok := err.(UnreferencedIncludesError)
+	require.True(t, ok)
+	require.Equal(t, []Data{{ID: "2822191", Type: "user"}}, auditErr.Items)
+
+	// Response is still decoded
+	require.Len(t, resp.Data, 1)
+
+	resp, err = client.FetchPublishedCampaigns(WithAuditIncludes())
+	require.Error(t, err)
+	require.NotNil(t, resp)
+	require.Len(t, resp.Data, 1)
+
+	_, err = client.FetchCampaign()
+	require.NoError(t, err)
+}
+
 func TestParseUnsupportedInclude(t *testing.T) {
 	includes := Includes{}
 	err := json.Unmarshal([]byte(unknownIncludeJson), &includes)
@@ -194,3 +285,40 @@ const unknownIncludeJson = `
 	}
 ]
 `
+
+const sharedIncludesJson = `
+{
+	"data": [
+		{
+			"attributes": {"amount_cents": 500},
+			"id": "100",
+			"relationships": {
+				"patron": {"data": {"id": "1", "type": "user"}},
+				"reward": {"data": {"id": "10", "type": "reward"}}
+			},
+			"type": "pledge"
+		},
+		{
+			"attributes": {"amount_cents": 500},
+			"id": "101",
+			"relationships": {
+				"patron": {"data": {"id": "1", "type": "user"}},
+				"reward": {"data": {"id": "10", "type": "reward"}}
+			},
+			"type": "pledge"
+		}
+	],
+	"included": [
+		{
+			"attributes": {"full_name": "Max"},
+			"id": "1",
+			"type": "user"
+		},
+		{
+			"attributes": {"amount": 500},
+			"id": "10",
+			"type": "reward"
+		}
+	]
+}
+`
@@ -0,0 +1,146 @@
+package patreon
+
+// PledgesIterator walks through pages of pledges to a campaign.
+//
+//	it := client.PledgesIterator(campaignId, WithPageSize(25))
+//	for it.Next() {
+//		for _, pledge := range it.Pledges() {
+//			// ...
+//		}
+//	}
+//	if err := it.Err(); err != nil {
+//		// ...
+//	}
+type PledgesIterator struct {
+	client     *Client
+	campaignId string
+	opts       []requestOption
+	maxPages   int
+
+	cursor string
+	pages  int
+	done   bool
+	resp   *PledgeResponse
+	err    error
+
+	// Set once the last page is fetched, unlike done which is also set on errors
+	finished bool
+
+	// Unreferenced includes of all pages, see WithAuditIncludes
+	orphans []Data
+}
+
+// PledgesIterator returns an iterator over pages of pledges to the provided campaignId.
+// The page size is controlled with WithPageSize, the number of pages can be limited with WithMaxPages.
+func (c *Client) PledgesIterator(campaignId string, opts ...requestOption) *PledgesIterator {
+	return &PledgesIterator{
+		client:     c,
+		campaignId: campaignId,
+		opts:       opts,
+		maxPages:   getOptions(opts...).maxPages,
+	}
+}
+
+// IteratorStateDone is the state of an iterator which has fetched all pages, see PledgesIterator.State.
+const IteratorStateDone = "done"
+
+// PledgesIteratorFromState returns an iterator resuming from the state saved with State,
+// so a long running job can continue after a restart where it left off.
+// The iterator restored from IteratorStateDone yields no pages.
+func (c *Client) PledgesIteratorFromState(campaignId, state string, opts ...requestOption) *PledgesIterator {
+	it := c.PledgesIterator(campaignId, opts...)
+	if state == IteratorStateDone {
+		it.done = true
+		it.finished = true
+	} else {
+		it.cursor = state
+	}
+
+	return it
+}
+
+// Next fetches the next page of pledges. It returns false when there are no more pages or the request failed (see Err).
+// The first page is always fetched, so a campaign without pledges yields a single page with no pledges.
+func (it *PledgesIterator) Next() bool {
+	if it.done {
+		return false
+	}
+
+	if it.maxPages > 0 && it.pages >= it.maxPages {
+		it.err = ErrMaxPagesExceeded
+		it.done = true
+		return false
+	}
+
+	resp, err := it.client.FetchPledges(it.campaignId, append(it.opts, WithCursor(it.cursor))...)
+	if auditErr, ok := err.(UnreferencedIncludesError); ok {
+		// The page is decoded, audit result is reported once all pages are fetched
+		it.orphans = append(it.orphans, auditErr.Items...)
+		err = nil
+	}
+
+	if err != nil {
+		it.err = err
+		it.done = true
+		return false
+	}
+
+	it.resp = resp
+	it.pages++
+
+	if resp.Links.Next == "" {
+		it.done = true
+		it.finished = true
+
+		if len(it.orphans) > 0 {
+			it.err = UnreferencedIncludesError{Items: it.orphans}
+		}
+	} else if cursor := resp.NextCursor(); cursor != "" {
+		it.cursor = cursor
+	} else {
+		// Requesting without cursor would start over from the first page
+		it.done = true
+		it.err = ErrMissingCursor
+	}
+
+	return true
+}
+
+// State returns the cursor of the next page to be fetched, which can be saved after processing the current page
+// to resume iteration later with PledgesIteratorFromState. It's empty before the first page and IteratorStateDone
+// after the last one. If iteration stopped with an error, the state points to the page which failed.
+func (it *PledgesIterator) State() string {
+	if it.finished {
+		return IteratorStateDone
+	}
+
+	return it.cursor
+}
+
+// Done reports whether all pages have been fetched.
+func (it *PledgesIterator) Done() bool {
+	return it.finished
+}
+
+// Pledges returns pledges of the current page.
+func (it *PledgesIterator) Pledges() []Pledge {
+	if it.resp == nil {
+		return nil
+	}
+
+	return it.resp.Data
+}
+
+// Response returns the current page response, including related resources.
+func (it *PledgesIterator) Response() *PledgeResponse {
+	return it.resp
+}
+
+// Err returns the error occurred during iteration, if any.
+// ErrMaxPagesExceeded is returned if there are more pages than allowed by WithMaxPages.
+// ErrMissingCursor is returned if the link to the next page has no cursor.
+// With WithAuditIncludes, pages are not interrupted by the audit; once the last page is fetched
+// UnreferencedIncludesError lists unreferenced includes of all pages.
+func (it *PledgesIterator) Err() error {
+	return it.err
+}
@@ -0,0 +1,149 @@
+package patreon
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPledgesIterator(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/oauth2/api/campaigns/123/pledges", func(writer http.ResponseWriter, request *http.Request) {
+		require.Equal(t, "2", request.URL.Query().Get("page[count]"))
+
+		if request.URL.Query().Get("page[cursor]") == "" {
+			fmt.Fprint(writer, activePledgesFirstPage)
+		} else {
+			require.Equal(t, "2017-07-03T23:25:08+00:00", request.URL.Query().Get("page[cursor]"))
+			fmt.Fprint(writer, activePledgesLastPage)
+		}
+	})
+
+	var ids []string
+	pages := 0
+
+	it := client.PledgesIterator("123", WithPageSize(2))
+	for it.Next() {
+		pages++
+		for _, pledge := range it.Pledges() {
+			ids = append(ids, pledge.ID)
+		}
+	}
+
+	require.NoError(t, it.Err())
+	require.Equal(t, 2, pages)
+	require.Equal(t, []string{"1", "2", "3", "4"}, ids)
+	require.False(t, it.Next())
+}
+
+func TestPledgesIteratorFromState(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/oauth2/api/campaigns/123/pledges", func(writer http.ResponseWriter, request *http.Request) {
+		if request.URL.Query().Get("page[cursor]") == "" {
+			fmt.Fprint(writer, activePledgesFirstPage)
+		} else {
+			require.Equal(t, "2017-07-03T23:25:08+00:00", request.URL.Query().Get("page[cursor]"))
+			fmt.Fprint(writer, activePledgesLastPage)
+		}
+	})
+
+	it := client.PledgesIterator("123")
+	require.Empty(t, it.State())
+	require.True(t, it.Next())
+
+	state := it.State()
+	require.Equal(t, "2017-07-03T23:25:08+00:00", state)
+
+	// Resume as if the job was restarted after the first page
+	it = client.PledgesIteratorFromState("123", state)
+	require.True(t, it.Next())
+	require.Equal(t, "3", it.Pledges()[0].ID)
+	require.True(t, it.Done())
+	require.Equal(t, IteratorStateDone, it.State())
+	require.False(t, it.Next())
+	require.NoError(t, it.Err())
+
+	// Resuming a finished job doesn't start over
+	it = client.PledgesIteratorFromState("123", it.State(), WithResponseInterceptor(func(*http.Response) error {
+		t.Fatal("unexpected request")
+		return nil
+	}))
+	require.True(t, it.Done())
+	require.False(t, it.Next())
+	require.NoError(t, it.Err())
+}
+
+func TestPledgesIteratorEmpty(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/oauth2/api/campaigns/123/pledges", func(writer http.ResponseWriter, request *http.Request) {
+		fmt.Fprint(writer, `{"data": [], "links": {}, "meta": {"count": 0}}`)
+	})
+
+	it := client.PledgesIterator("123")
+	require.True(t, it.Next())
+	require.Empty(t, it.Pledges())
+	require.False(t, it.Next())
+	require.NoError(t, it.Err())
+}
+
+func TestPledgesIteratorError(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/oauth2/api/campaigns/123/pledges", func(writer http.ResponseWriter, request *http.Request) {
+		writer.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(writer, errorResp)
+	})
+
+	it := client.PledgesIterator("123")
+	require.False(t, it.Next())
+	require.Error(t, it.Err())
+	require.Nil(t, it.Pledges())
+}
+
+func TestPledgesIteratorMissingCursor(t *testing.T) {
+	setup()
+	defer teardown()
+
+	requests := 0
+	mux.HandleFunc("/oauth2/api/campaigns/123/pledges", func(writer http.ResponseWriter, request *http.Request) {
+		requests++
+		fmt.Fprint(writer, `{"data": [], "links": {"next": "https://www.patreon.com/api/oauth2/api/campaigns/123/pledges?page%5Bcount%5D=2"}}`)
+	})
+
+	it := client.PledgesIterator("123")
+	require.True(t, it.Next())
+	require.False(t, it.Next())
+	require.Equal(t, ErrMissingCursor, it.Err())
+	require.Equal(t, 1, requests)
+}
+
+func TestPledgesIteratorMaxPages(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/oauth2/api/campaigns/123/pledges", func(writer http.ResponseWriter, request *http.Request) {
+		fmt.Fprint(writer, activePledgesFirstPage)
+	})
+
+	pages := 0
+	it := client.PledgesIterator("123", WithMaxPages(2))
+	for it.Next() {
+		pages++
+	}
+
+	require.Equal(t, 2, pages)
+	require.Equal(t, ErrMaxPagesExceeded, it.Err())
+
+	// Remaining pages can be fetched after resuming
+	require.False(t, it.Done())
+	require.Equal(t, "2017-07-03T23:25:08+00:00", it.State())
+}
@@ -0,0 +1,76 @@
+package patreon
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// TokenResponse represents Patreon's OAuth2 token endpoint response (see https://tools.ietf.org/html/rfc6749#section-5.1).
+type TokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"`
+	Scope        string `json:"scope"`
+
+	// Expiry is the time the access token expires, calculated from ExpiresIn when the response is received
+	Expiry time.Time `json:"-"`
+}
+
+// AuthorizationCodeURL returns the URL of Patreon's consent page to redirect the user to (see https://tools.ietf.org/html/rfc6749#section-4.1.1).
+// Once the user grants access, Patreon redirects back to redirectURI with the authorization code and the same state.
+// Use Scope* constants to specify scopes.
+func AuthorizationCodeURL(clientID, redirectURI string, scopes []string, state string) string {
+	query := url.Values{}
+	query.Set("response_type", "code")
+	query.Set("client_id", clientID)
+	query.Set("redirect_uri", redirectURI)
+
+	if len(scopes) > 0 {
+		query.Set("scope", Scopes(scopes...))
+	}
+
+	if state != "" {
+		query.Set("state", state)
+	}
+
+	return AuthorizationURL + "?" + query.Encode()
+}
+
+// RefreshToken exchanges a refresh token for a new access token (see https://tools.ietf.org/html/rfc6749#section-6).
+// The request is sent to AccessTokenURL (or the token endpoint of the host set with WithBaseURL) without
+// the client's authentication, only the timeout of the client's http.Client is applied.
+func (c *Client) RefreshToken(clientID, clientSecret, refreshToken string) (*TokenResponse, error) {
+	if c.baseURLErr != nil {
+		return nil, c.baseURLErr
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "refresh_token")
+	form.Set("refresh_token", refreshToken)
+	form.Set("client_id", clientID)
+	form.Set("client_secret", clientSecret)
+
+	req, err := http.NewRequest("POST", c.baseURL+"/oauth2/token", strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	// The client's http.Client usually authenticates with the access token being replaced (and may try to refresh it)
+	plain := &http.Client{Timeout: c.httpClient.Timeout}
+
+	resp := &TokenResponse{}
+	if err := c.do(req, resp, options{httpClient: plain}); err != nil {
+		return nil, err
+	}
+
+	if resp.ExpiresIn > 0 {
+		resp.Expiry = c.now().Add(time.Duration(resp.ExpiresIn) * time.Second)
+	}
+
+	return resp, nil
+}
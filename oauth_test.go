@@ -0,0 +1,113 @@
+package patreon
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthorizationCodeURL(t *testing.T) {
+	addr := AuthorizationCodeURL("123", "https://example.com/callback", []string{ScopeUsers, ScopePledgesToMe}, "xyz")
+	require.Equal(t, "https://www.patreon.com/oauth2/authorize?client_id=123&redirect_uri=https%3A%2F%2Fexample.com%2Fcallback&response_type=code&scope=users+pledges-to-me&state=xyz", addr)
+
+	addr = AuthorizationCodeURL("123", "https://example.com/callback", nil, "")
+	require.Equal(t, "https://www.patreon.com/oauth2/authorize?client_id=123&redirect_uri=https%3A%2F%2Fexample.com%2Fcallback&response_type=code", addr)
+}
+
+func TestRefreshToken(t *testing.T) {
+	setup()
+	defer teardown()
+
+	now := time.Date(2017, 6, 1, 0, 0, 0, 0, time.UTC)
+	client.now = func() time.Time { return now }
+
+	mux.HandleFunc("/oauth2/token", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "POST", r.Method)
+		require.Equal(t, "application/x-www-form-urlencoded", r.Header.Get("Content-Type"))
+		require.NoError(t, r.ParseForm())
+		require.Equal(t, "refresh_token", r.PostForm.Get("grant_type"))
+		require.Equal(t, "456", r.PostForm.Get("refresh_token"))
+		require.Equal(t, "client", r.PostForm.Get("client_id"))
+		require.Equal(t, "secret", r.PostForm.Get("client_secret"))
+
+		fmt.Fprint(w, refreshTokenResp)
+	})
+
+	resp, err := client.RefreshToken("client", "secret", "456")
+	require.NoError(t, err)
+	require.Equal(t, "789", resp.AccessToken)
+	require.Equal(t, "012", resp.RefreshToken)
+	require.Equal(t, "Bearer", resp.TokenType)
+	require.Equal(t, "users pledges-to-me", resp.Scope)
+	require.Equal(t, 2678400, resp.ExpiresIn)
+	require.Equal(t, now.Add(31*24*time.Hour), resp.Expiry)
+}
+
+// authTransport authenticates requests like the client returned by oauth2.Config.Client
+type authTransport struct{}
+
+func (authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// RoundTrip must not modify the request
+	authReq := *req
+	authReq.Header = http.Header{}
+	for key, values := range req.Header {
+		authReq.Header[key] = values
+	}
+
+	authReq.Header.Set("Authorization", "Bearer expired")
+	return http.DefaultTransport.RoundTrip(&authReq)
+}
+
+func TestRefreshTokenWithoutAuthentication(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/oauth2/token", func(w http.ResponseWriter, r *http.Request) {
+		require.Empty(t, r.Header.Get("Authorization"))
+		fmt.Fprint(w, refreshTokenResp)
+	})
+
+	mux.HandleFunc("/oauth2/api/current_user", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "Bearer expired", r.Header.Get("Authorization"))
+		fmt.Fprint(w, currentUserResp)
+	})
+
+	client := NewClient(&http.Client{Transport: authTransport{}}, WithBaseURL(server.URL))
+
+	_, err := client.FetchUser()
+	require.NoError(t, err)
+
+	resp, err := client.RefreshToken("client", "secret", "456")
+	require.NoError(t, err)
+	require.Equal(t, "789", resp.AccessToken)
+}
+
+func TestRefreshTokenError(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/oauth2/token", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(w, `{"error": "invalid_grant"}`)
+	})
+
+	_, err := client.RefreshToken("client", "secret", "456")
+	require.Error(t, err)
+
+	apiErr, ok := err.(APIError)
+	require.True(t, ok)
+	require.Equal(t, http.StatusUnauthorized, apiErr.StatusCode)
+}
+
+const refreshTokenResp = `
+{
+    "access_token": "789",
+    "refresh_token": "012",
+    "expires_in": 2678400,
+    "scope": "users pledges-to-me",
+    "token_type": "Bearer"
+}
+`
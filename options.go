@@ -1,33 +1,106 @@
 package patreon
 
 import (
+	"net/http"
 	"net/url"
 	"strings"
+	"time"
 )
 
 type options struct {
-	fields  map[string]string
-	include string
-	size    int
-	cursor  string
+	fields       map[string]string
+	include      string
+	sort         []string
+	filters      map[string]string
+	size         int
+	cursor       string
+	interceptors []func(*http.Response) error
+	notFoundOK   bool
+	timeout      time.Duration
+	audit        bool
+	maxPages     int
+	minimal      bool
+
+	// httpClient overrides the client's http.Client (not exposed as an option)
+	httpClient *http.Client
 }
 
 type requestOption func(*options)
 
-// WithFields specifies the resource attributes you want to be returned by API.
+// Resource names to be used with WithFields.
+const (
+	FieldsUser     = "user"
+	FieldsCampaign = "campaign"
+	FieldsPledge   = "pledge"
+	FieldsReward   = "reward"
+	FieldsGoal     = "goal"
+	FieldsCard     = "card"
+	FieldsAddress  = "address"
+)
+
+// minimalFields specifies the essential attributes requested by WithMinimalResponse.
+var minimalFields = map[string]string{
+	FieldsUser:     "full_name",
+	FieldsCampaign: "creation_name",
+	FieldsPledge:   "amount_cents,declined_since",
+	FieldsReward:   "amount_cents,title",
+	FieldsGoal:     "amount_cents,title",
+}
+
+// WithFields specifies the resource attributes you want to be returned by API (see Fields* constants for resource names).
+// Fields of repeated calls for the same resource are merged.
 func WithFields(resource string, fields ...string) requestOption {
 	return func(o *options) {
 		if o.fields == nil {
 			o.fields = make(map[string]string)
 		}
-		o.fields[resource] = strings.Join(fields, ",")
+
+		list := strings.Join(fields, ",")
+		if existing, ok := o.fields[resource]; ok && existing != "" {
+			list = existing + "," + list
+		}
+
+		o.fields[resource] = list
+	}
+}
+
+// WithMinimalResponse limits the attributes returned by API to the essential ones for every resource.
+// Resources configured explicitly with WithFields keep their own attribute list.
+func WithMinimalResponse() requestOption {
+	return func(o *options) {
+		o.minimal = true
 	}
 }
 
 // WithIncludes specifies the related resources you want to be returned by API.
+// Relationships of repeated calls are merged, duplicates are sent once.
 func WithIncludes(include ...string) requestOption {
 	return func(o *options) {
-		o.include = strings.Join(include, ",")
+		list := strings.Join(include, ",")
+		if o.include != "" {
+			list = o.include + "," + list
+		}
+
+		o.include = list
+	}
+}
+
+// WithSort specifies the attribute to sort the returned items by, prefix it with '-' for descending order
+// (for example WithSort("-created_at")). Repeated calls add secondary sort attributes in order of calls.
+func WithSort(field string) requestOption {
+	return func(o *options) {
+		o.sort = append(o.sort, field)
+	}
+}
+
+// WithFilter returns only the items which attribute key equals value (sent as filter[key]=value).
+// Multiple filters can be combined, the latest value wins for a repeated key.
+func WithFilter(key, value string) requestOption {
+	return func(o *options) {
+		if o.filters == nil {
+			o.filters = make(map[string]string)
+		}
+		o.filters[key] = value
 	}
 }
 
@@ -41,8 +114,9 @@ func WithPageSize(size int) requestOption {
 // WithCursor controls cursor-based pagination. Cursor will also be extracted from navigation links for convenience.
 func WithCursor(cursor string) requestOption {
 	return func(o *options) {
-		u, err := url.ParseRequestURI(cursor)
-		if err == nil {
+		// Only absolute links are treated as navigation links, opaque cursors may contain '/' and other URL characters
+		u, err := url.Parse(cursor)
+		if err == nil && u.IsAbs() && u.Host != "" {
 			cursor = u.Query().Get("page[cursor]")
 		}
 
@@ -50,11 +124,66 @@ func WithCursor(cursor string) requestOption {
 	}
 }
 
+// WithResponseInterceptor registers a function to inspect the raw HTTP response before it's decoded.
+// Returning an error aborts the request with that error.
+func WithResponseInterceptor(fn func(*http.Response) error) requestOption {
+	return func(o *options) {
+		o.interceptors = append(o.interceptors, fn)
+	}
+}
+
+// WithEmptyOnNotFound makes list methods (FetchPledges and methods walking through pages of pledges) return
+// an empty response instead of an error when API responds with 404 Not Found. Other methods ignore it.
+func WithEmptyOnNotFound() requestOption {
+	return func(o *options) {
+		o.notFoundOK = true
+	}
+}
+
+// WithRequestTimeout limits the time for a single call, including reading the response body, all attempts and waiting
+// between them (see WithRetry) and waiting for a slot (see WithMaxInFlight). RequestError reporting Timeout is
+// returned once the time is exceeded. It overrides the timeout of the client's http.Client for this call only.
+func WithRequestTimeout(timeout time.Duration) requestOption {
+	return func(o *options) {
+		o.timeout = timeout
+	}
+}
+
+// WithAuditIncludes verifies that every included resource of the response is referenced by some relationship,
+// the request returns UnreferencedIncludesError otherwise. The response is still decoded and returned with the error,
+// methods walking through all pages report unreferenced includes of all pages once they're fetched.
+// This is intended for debugging and tests.
+func WithAuditIncludes() requestOption {
+	return func(o *options) {
+		o.audit = true
+	}
+}
+
+// WithMaxPages limits the number of pages fetched by PledgesIterator and methods walking through all pages (such as FetchAllActivePledges).
+// ErrMaxPagesExceeded is returned when there are more pages than the limit.
+func WithMaxPages(n int) requestOption {
+	return func(o *options) {
+		o.maxPages = n
+	}
+}
+
 func getOptions(opts ...requestOption) options {
 	cfg := options{}
 	for _, fn := range opts {
 		fn(&cfg)
 	}
 
+	if cfg.minimal {
+		if cfg.fields == nil {
+			cfg.fields = make(map[string]string)
+		}
+
+		for resource, fields := range minimalFields {
+			if _, ok := cfg.fields[resource]; !ok {
+				cfg.fields[resource] = fields
+			}
+		}
+	}
+
 	return cfg
 }
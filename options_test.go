@@ -23,3 +23,64 @@ func TestWithCursor(t *testing.T) {
 
 	require.Equal(t, "2017-01-19T18:39:17+00:00", opt.cursor)
 }
+
+func TestWithCursorOpaque(t *testing.T) {
+	for _, cursor := range []string{"/w8+abc=", "abc/def+ghi==", "a+b=c"} {
+		opt := getOptions(WithCursor(cursor))
+		require.Equal(t, cursor, opt.cursor)
+	}
+}
+
+func TestWithCursorURLEncoded(t *testing.T) {
+	opt := getOptions(WithCursor("https://www.patreon.com/api/oauth2/api/campaigns/123456/pledges?page%5Bcursor%5D=%2Fw8%2Babc%3D"))
+	require.Equal(t, "/w8+abc=", opt.cursor)
+}
+
+func TestWithMinimalResponse(t *testing.T) {
+	opt := getOptions(WithMinimalResponse())
+	require.Equal(t, "amount_cents,declined_since", opt.fields["pledge"])
+	require.Equal(t, "full_name", opt.fields["user"])
+}
+
+func TestWithMinimalResponseOverride(t *testing.T) {
+	opt := getOptions(WithFields("user", "email"), WithMinimalResponse())
+	require.Equal(t, "email", opt.fields["user"])
+	require.Equal(t, "amount_cents,declined_since", opt.fields["pledge"])
+
+	opt = getOptions(WithMinimalResponse(), WithFields("user", "email"))
+	require.Equal(t, "email", opt.fields["user"])
+}
+
+func TestWithFieldsMerge(t *testing.T) {
+	opt := getOptions(
+		WithFields(FieldsPledge, "amount_cents"),
+		WithFields(FieldsUser, "email"),
+		WithFields(FieldsPledge, "declined_since", "created_at"),
+	)
+
+	require.Equal(t, "amount_cents,declined_since,created_at", opt.fields["pledge"])
+	require.Equal(t, "email", opt.fields["user"])
+}
+
+func TestWithFieldsDuplicates(t *testing.T) {
+	client := NewClient(nil)
+
+	addr, err := client.buildURL("/path",
+		WithFields(FieldsPledge, "amount_cents"),
+		WithFields(FieldsPledge, "amount_cents", "created_at"),
+	)
+	require.NoError(t, err)
+	require.Equal(t, "https://api.patreon.com/path?fields%5Bpledge%5D=amount_cents%2Ccreated_at", addr)
+}
+
+func TestWithIncludesMerge(t *testing.T) {
+	client := NewClient(nil)
+
+	addr, err := client.buildURL("/path",
+		WithIncludes("reward"),
+		WithIncludes("patron", "reward"),
+		WithIncludes("creator"),
+	)
+	require.NoError(t, err)
+	require.Equal(t, "https://api.patreon.com/path?include=creator%2Cpatron%2Creward", addr)
+}
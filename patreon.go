@@ -3,9 +3,15 @@ package patreon
 import (
 	"encoding/json"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"net/http"
 	"net/url"
+	"sort"
 	"strconv"
+	"strings"
+	"sync"
+	"time"
 )
 
 const (
@@ -20,24 +26,109 @@ const (
 
 const (
 	baseURL = "https://api.patreon.com"
+
+	// maxConcurrentCampaigns limits the number of campaigns fetched in parallel
+	maxConcurrentCampaigns = 4
+
+	// maxRetryDelay caps the exponential backoff between retries
+	maxRetryDelay = time.Minute
+
+	// statusTooManyRequests is defined here as http.StatusTooManyRequests is not available prior go1.6
+	statusTooManyRequests = 429
 )
 
 // Client manages communication with Patreon API.
 type Client struct {
 	httpClient *http.Client
 	baseURL    string
+	baseURLErr error
+	now        func() time.Time
+	inFlight   chan struct{}
+
+	// Retry policy, see WithRetry
+	maxRetries     int
+	retryBaseDelay time.Duration
+	retryable      func(*http.Response) bool
+
+	// Cached campaign response, see CachedCampaign
+	campaignMu        sync.Mutex
+	campaign          *CampaignResponse
+	campaignFetchedAt time.Time
+}
+
+type clientOption func(*Client)
+
+// WithClock overrides the function used by the client to get the current time (time.Now by default).
+// It's mostly useful to test time dependent logic.
+func WithClock(now func() time.Time) clientOption {
+	return func(c *Client) {
+		c.now = now
+	}
+}
+
+// WithMaxInFlight limits the number of concurrent requests across all goroutines sharing the client.
+// Requests over the limit wait for a slot (or until request's Cancel channel is closed or its context is done).
+func WithMaxInFlight(n int) clientOption {
+	return func(c *Client) {
+		if n > 0 {
+			c.inFlight = make(chan struct{}, n)
+		}
+	}
+}
+
+// WithRetry makes the client retry requests failed with 429 Too Many Requests or 5xx status up to maxRetries times.
+// The delay before each attempt is taken from Retry-After header, if present, or doubled from baseDelay otherwise
+// (up to a minute). Waiting is interrupted when request's Cancel channel is closed or its context is done, the slot taken with
+// WithMaxInFlight is released while waiting. Requests with body are not retried.
+func WithRetry(maxRetries int, baseDelay time.Duration) clientOption {
+	return func(c *Client) {
+		c.maxRetries = maxRetries
+		c.retryBaseDelay = baseDelay
+	}
+}
+
+// WithBaseURL points the client to a different API host (such as a proxy mirroring Patreon API or a test server).
+// The URL must be absolute, otherwise all client's requests fail with the parse error.
+func WithBaseURL(rawurl string) clientOption {
+	return func(c *Client) {
+		u, err := url.Parse(rawurl)
+		if err == nil && (!u.IsAbs() || u.Host == "") {
+			err = fmt.Errorf("base url '%s' is not absolute", rawurl)
+		}
+
+		if err != nil {
+			c.baseURLErr = err
+			return
+		}
+
+		c.baseURL = strings.TrimRight(rawurl, "/")
+		c.baseURLErr = nil
+	}
+}
+
+// WithErrorClassifier overrides which responses are retried by the policy set with WithRetry
+// (by default 429 Too Many Requests and 5xx statuses).
+func WithErrorClassifier(retryable func(resp *http.Response) bool) clientOption {
+	return func(c *Client) {
+		c.retryable = retryable
+	}
 }
 
 // NewClient returns a new Patreon API client. If a nil httpClient is
 // provided, http.DefaultClient will be used. To use API methods which require
 // authentication, provide an http.Client that will perform the authentication
 // for you (such as that provided by the golang.org/x/oauth2 library).
-func NewClient(httpClient *http.Client) *Client {
+func NewClient(httpClient *http.Client, opts ...clientOption) *Client {
 	if httpClient == nil {
 		httpClient = http.DefaultClient
 	}
 
-	return &Client{httpClient: httpClient, baseURL: baseURL}
+	c := &Client{httpClient: httpClient, baseURL: baseURL, now: time.Now, retryable: isRetryable}
+	for _, fn := range opts {
+		fn(c)
+	}
+
+	return c
 }
 
 // Client returns the HTTP client configured for this client.
@@ -64,18 +155,227 @@ func (c *Client) FetchCampaign(opts ...requestOption) (*CampaignResponse, error)
 	return resp, err
 }
 
+// FetchPublishedCampaigns fetches your campaigns like FetchCampaign, but leaves out unpublished (draft) ones.
+func (c *Client) FetchPublishedCampaigns(opts ...requestOption) (*CampaignResponse, error) {
+	resp, err := c.FetchCampaign(opts...)
+	if err != nil && !isAuditError(err) {
+		return nil, err
+	}
+
+	published := resp.Data[:0]
+	for _, campaign := range resp.Data {
+		if campaign.IsPublished() {
+			published = append(published, campaign)
+		}
+	}
+
+	resp.Data = published
+	return resp, err
+}
+
+// CachedCampaign returns your campaign info (with rewards, creator and goals included) fetched at most ttl ago.
+// Concurrent callers wait for a single API request on cache miss, errors are not cached.
+// The returned response is shared between callers and must not be modified.
+func (c *Client) CachedCampaign(ttl time.Duration) (*CampaignResponse, error) {
+	c.campaignMu.Lock()
+	defer c.campaignMu.Unlock()
+
+	if c.campaign != nil && c.now().Sub(c.campaignFetchedAt) < ttl {
+		return c.campaign, nil
+	}
+
+	resp, err := c.FetchCampaign(WithIncludes(CampaignDefaultRelations))
+	if err != nil {
+		return nil, err
+	}
+
+	c.campaign = resp
+	c.campaignFetchedAt = c.now()
+
+	return resp, nil
+}
+
 // FetchPledges fetches a list of pledges to you.
 // This API returns a list of pledges to the provided campaignId. They are sorted by the date the pledge was made,
 // and provide relationship references to the users who made each respective pledge. The API response will also contain
 // a links section which may be used to fetch the next page of pledges, or go back to the first page.
 func (c *Client) FetchPledges(campaignId string, opts ...requestOption) (*PledgeResponse, error) {
+	if campaignId == "" {
+		return nil, ErrEmptyCampaignID
+	}
+
 	resp := &PledgeResponse{}
 	path := fmt.Sprintf("/oauth2/api/campaigns/%s/pledges", campaignId)
 	err := c.get(path, resp, opts...)
+	if isNotFound(err) && getOptions(opts...).notFoundOK {
+		return resp, nil
+	}
+
 	return resp, err
 }
 
+// FetchAllActivePledges fetches all pages of pledges to the provided campaignId and returns the ones which are not declined or paused.
+// Patrons and rewards are included by default (WithIncludes adds more), as well as pledge's is_paused attribute
+// (WithFields adds more). Related resources from all pages are merged into a single Included list.
+// If the number of pages exceeds the limit set with WithMaxPages, the pledges fetched so far are returned along with
+// ErrMaxPagesExceeded. With WithAuditIncludes, all pages are returned along with UnreferencedIncludesError.
+func (c *Client) FetchAllActivePledges(campaignId string, opts ...requestOption) (*PledgeResponse, error) {
+	// is_paused is optional and must be requested explicitly to tell paused pledges apart
+	opts = append([]requestOption{WithIncludes("patron", "reward"), WithFields(FieldsPledge, "is_paused")}, opts...)
+
+	all := &PledgeResponse{}
+
+	it := c.PledgesIterator(campaignId, opts...)
+	for first := true; it.Next(); first = false {
+		resp := it.Response()
+
+		if first {
+			all.Links.First = resp.Links.First
+			all.Meta = resp.Meta
+		}
+
+		for _, pledge := range resp.Data {
+			if pledge.IsActive() {
+				all.Data = append(all.Data, pledge)
+			}
+		}
+
+		all.Included.merge(resp.Included)
+	}
+
+	if err := it.Err(); err != nil {
+		if err == ErrMaxPagesExceeded || isAuditError(err) {
+			return all, err
+		}
+
+		return nil, err
+	}
+
+	return all, nil
+}
+
+// CheckAccess checks whether the user is an active patron of the campaign and which rewards (tiers) the user is
+// entitled to, e.g. to gate content. As v1 API doesn't support looking up a pledge by patron, all active pledges
+// of the campaign are fetched. A user without an active pledge gets an AccessResult with IsActivePatron set to false.
+// With WithAuditIncludes, the result is returned along with UnreferencedIncludesError.
+func (c *Client) CheckAccess(campaignId, userId string, opts ...requestOption) (*AccessResult, error) {
+	resp, err := c.FetchAllActivePledges(campaignId, opts...)
+	if err != nil && !isAuditError(err) {
+		return nil, err
+	}
+
+	result := &AccessResult{}
+	for _, pledge := range resp.Data {
+		if pledge.Relationships.Patron == nil || pledge.Relationships.Patron.Data.ID != userId {
+			continue
+		}
+
+		result.IsActivePatron = true
+		result.EntitledAmountCents += int64(pledge.Attributes.AmountCents)
+
+		if pledge.Relationships.Reward != nil && pledge.Relationships.Reward.Data.ID != "" {
+			result.EntitledTierIDs = append(result.EntitledTierIDs, pledge.Relationships.Reward.Data.ID)
+		}
+	}
+
+	return result, err
+}
+
+// CampaignErrors maps campaign IDs to the errors occurred while fetching them.
+type CampaignErrors map[string]error
+
+func (e CampaignErrors) Error() string {
+	ids := make([]string, 0, len(e))
+	for id := range e {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	msgs := make([]string, len(ids))
+	for idx, id := range ids {
+		msgs[idx] = fmt.Sprintf("campaign %s: %v", id, e[id])
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
+// FetchAllActivePledgesForCampaigns runs FetchAllActivePledges for each of the provided campaigns concurrently and
+// returns responses keyed by campaign ID. If some campaigns fail, the responses of the others are returned along
+// with CampaignErrors. Partial responses returned by FetchAllActivePledges with an error (such as ErrMaxPagesExceeded
+// or UnreferencedIncludesError) are kept as well.
+func (c *Client) FetchAllActivePledgesForCampaigns(campaignIds []string, opts ...requestOption) (map[string]*PledgeResponse, error) {
+	var (
+		mu        sync.Mutex
+		wg        sync.WaitGroup
+		responses = make(map[string]*PledgeResponse)
+		errs      = make(CampaignErrors)
+		limit     = make(chan struct{}, maxConcurrentCampaigns)
+	)
+
+	for _, id := range campaignIds {
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+
+			limit <- struct{}{}
+			resp, err := c.FetchAllActivePledges(id, opts...)
+			<-limit
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			// Partial responses (see FetchAllActivePledges) are kept along with the error
+			if resp != nil {
+				responses[id] = resp
+			}
+
+			if err != nil {
+				errs[id] = err
+			}
+		}(id)
+	}
+
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return responses, errs
+	}
+
+	return responses, nil
+}
+
+// CampaignMRR calculates the campaign's monthly recurring revenue as a sum of active pledges.
+// The amount is returned in cents along with the pledges currency, an error is returned if pledges differ in currency.
+// Note that for campaigns charged per creation (see IsMonthly) the amount is per creation, rather than per month.
+func (c *Client) CampaignMRR(campaignId string) (int64, string, error) {
+	resp, err := c.FetchAllActivePledges(campaignId)
+	if err != nil {
+		return 0, "", err
+	}
+
+	var (
+		cents    int64
+		currency string
+	)
+
+	for _, pledge := range resp.Data {
+		if currency == "" {
+			currency = pledge.Attributes.Currency
+		} else if pledge.Attributes.Currency != "" && pledge.Attributes.Currency != currency {
+			return 0, "", fmt.Errorf("pledges are in different currencies: %s, %s", currency, pledge.Attributes.Currency)
+		}
+
+		cents += int64(pledge.Attributes.AmountCents)
+	}
+
+	return cents, currency, nil
+}
+
 func (c *Client) buildURL(path string, opts ...requestOption) (string, error) {
+	if c.baseURLErr != nil {
+		return "", c.baseURLErr
+	}
+
 	cfg := getOptions(opts...)
 
 	u, err := url.ParseRequestURI(c.baseURL + path)
@@ -83,49 +383,318 @@ func (c *Client) buildURL(path string, opts ...requestOption) (string, error) {
 		return "", err
 	}
 
-	q := url.Values{}
-	if cfg.include != "" {
-		q.Set("include", cfg.include)
-	}
-
+	// Query parameters are emitted in a fixed order of groups: fields, include, filter, sort, page.
+	// Includes and fields are sorted (as well as keys within a group by url.Values.Encode)
+	// so the same request always produces the same URL, which keeps it cacheable
+	fields := url.Values{}
 	if len(cfg.fields) > 0 {
-		for resource, fields := range cfg.fields {
+		for resource, list := range cfg.fields {
 			key := fmt.Sprintf("fields[%s]", resource)
-			q.Set(key, fields)
+			fields.Set(key, sortList(list))
 		}
 	}
 
+	include := url.Values{}
+	if cfg.include != "" {
+		include.Set("include", sortList(cfg.include))
+	}
+
+	filter := url.Values{}
+	for key, value := range cfg.filters {
+		filter.Set(fmt.Sprintf("filter[%s]", key), value)
+	}
+
+	// Sort attributes are kept in the order they were specified as it defines their priority
+	sorting := url.Values{}
+	if len(cfg.sort) > 0 {
+		sorting.Set("sort", strings.Join(cfg.sort, ","))
+	}
+
+	page := url.Values{}
 	if cfg.size != 0 {
-		q.Set("page[count]", strconv.Itoa(cfg.size))
+		page.Set("page[count]", strconv.Itoa(cfg.size))
 	}
 
 	if cfg.cursor != "" {
-		q.Set("page[cursor]", cfg.cursor)
+		page.Set("page[cursor]", cfg.cursor)
 	}
 
-	u.RawQuery = q.Encode()
+	u.RawQuery = encodeQuery(fields, include, filter, sorting, page)
 	return u.String(), nil
 }
 
+// encodeQuery encodes groups of query parameters preserving the order of groups.
+func encodeQuery(groups ...url.Values) string {
+	var parts []string
+	for _, group := range groups {
+		if len(group) > 0 {
+			parts = append(parts, group.Encode())
+		}
+	}
+
+	return strings.Join(parts, "&")
+}
+
+// isRetryable reports whether the request failed with the status that is worth retrying.
+// It's the default error classifier, see WithErrorClassifier.
+func isRetryable(resp *http.Response) bool {
+	return resp.StatusCode == statusTooManyRequests || resp.StatusCode >= 500
+}
+
+// retryDelay returns the delay before the next attempt, as requested by Retry-After header
+// (either in seconds or as HTTP date) or calculated with exponential backoff.
+func (c *Client) retryDelay(resp *http.Response, attempt int) time.Duration {
+	if header := resp.Header.Get("Retry-After"); header != "" {
+		if seconds, err := strconv.Atoi(header); err == nil && seconds >= 0 {
+			return time.Duration(seconds) * time.Second
+		}
+
+		if date, err := http.ParseTime(header); err == nil {
+			if delay := date.Sub(c.now()); delay > 0 {
+				return delay
+			}
+
+			return 0
+		}
+	}
+
+	delay := c.retryBaseDelay
+	for i := 0; i < attempt && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+
+	if delay > maxRetryDelay {
+		return maxRetryDelay
+	}
+
+	return delay
+}
+
+// readError builds an error from a non-2xx response. Responses which are not Patreon's JSON:API errors
+// (such as HTML pages from proxies or empty bodies) are returned as APIError.
+func readError(resp *http.Response) error {
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	errs := ErrorResponse{}
+	if err := json.Unmarshal(body, &errs); err != nil || len(errs.Errors) == 0 {
+		return APIError{StatusCode: resp.StatusCode, Body: string(body)}
+	}
+
+	errs.StatusCode = resp.StatusCode
+	return errs
+}
+
+// isNotFound reports whether the request failed with 404 Not Found status.
+func isNotFound(err error) bool {
+	switch e := err.(type) {
+	case ErrorResponse:
+		return e.StatusCode == http.StatusNotFound
+	case APIError:
+		return e.StatusCode == http.StatusNotFound
+	}
+
+	return false
+}
+
+// sortList sorts items of a comma separated list and removes duplicates.
+func sortList(list string) string {
+	items := strings.Split(list, ",")
+	sort.Strings(items)
+
+	unique := items[:0]
+	for idx, item := range items {
+		if item == "" || (idx > 0 && item == items[idx-1]) {
+			continue
+		}
+		unique = append(unique, item)
+	}
+
+	return strings.Join(unique, ",")
+}
+
+// Do sends a custom API request and decodes the JSON response into v (if v is not nil).
+// It's useful to access API endpoints not yet covered by the client, while sharing its authentication and error handling.
+// Non-2xx responses are returned as ErrorResponse, or as APIError if the body is not a Patreon error response.
+func (c *Client) Do(req *http.Request, v interface{}) error {
+	return c.do(req, v, options{})
+}
+
 func (c *Client) get(path string, v interface{}, opts ...requestOption) error {
 	addr, err := c.buildURL(path, opts...)
 	if err != nil {
 		return err
 	}
 
-	resp, err := c.httpClient.Get(addr)
+	req, err := http.NewRequest("GET", addr, nil)
 	if err != nil {
 		return err
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		errs := ErrorResponse{}
-		if err := json.NewDecoder(resp.Body).Decode(&errs); err != nil {
+	return c.do(req, v, getOptions(opts...))
+}
+
+// acquire takes a slot for the request if the number of concurrent requests is limited (see WithMaxInFlight).
+// Waiting is interrupted when request's Cancel channel is closed, its context is done or expired fires.
+func (c *Client) acquire(req *http.Request, expired <-chan time.Time) error {
+	if c.inFlight == nil {
+		return nil
+	}
+
+	select {
+	case c.inFlight <- struct{}{}:
+		return nil
+	case <-req.Cancel:
+		return ErrRequestCanceled
+	case <-requestDone(req):
+		return ErrRequestCanceled
+	case <-expired:
+		return timeoutError{}
+	}
+}
+
+// release frees the slot taken with acquire.
+func (c *Client) release() {
+	if c.inFlight != nil {
+		<-c.inFlight
+	}
+}
+
+func (c *Client) do(req *http.Request, v interface{}, cfg options) error {
+	// Elapsed time of RequestError includes all attempts and waiting between them
+	start := c.now()
+
+	// The timeout set with WithRequestTimeout covers the whole call (all attempts and waiting)
+	var (
+		deadline time.Time
+		expired  <-chan time.Time
+	)
+
+	if cfg.timeout > 0 {
+		deadline = time.Now().Add(cfg.timeout)
+		timer := time.NewTimer(cfg.timeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+
+	timedOut := func() error {
+		return RequestError{Err: timeoutError{}, Elapsed: c.now().Sub(start)}
+	}
+
+	if err := c.acquire(req, expired); err != nil {
+		if err == (timeoutError{}) {
+			return timedOut()
+		}
+
+		return err
+	}
+
+	held := true
+	defer func() {
+		if held {
+			c.release()
+		}
+	}()
+
+	httpClient := c.httpClient
+	if cfg.httpClient != nil {
+		httpClient = cfg.httpClient
+	}
+
+	var resp *http.Response
+	for attempt := 0; ; attempt++ {
+		attemptClient := httpClient
+		if cfg.timeout > 0 {
+			remaining := deadline.Sub(time.Now())
+			if remaining <= 0 {
+				return timedOut()
+			}
+
+			// Shallow copy shares the transport (and authentication) with the client
+			withTimeout := *httpClient
+			withTimeout.Timeout = remaining
+			attemptClient = &withTimeout
+		}
+
+		var err error
+		resp, err = attemptClient.Do(req)
+		if err != nil {
+			return RequestError{Err: err, Elapsed: c.now().Sub(start)}
+		}
+
+		// Requests with body can't be replayed
+		if attempt >= c.maxRetries || req.Body != nil || !c.retryable(resp) {
+			break
+		}
+
+		delay := c.retryDelay(resp, attempt)
+
+		io.Copy(ioutil.Discard, resp.Body)
+		resp.Body.Close()
+
+		// Don't block other requests while waiting
+		c.release()
+		held = false
+
+		select {
+		case <-time.After(delay):
+		case <-req.Cancel:
+			return ErrRequestCanceled
+		case <-requestDone(req):
+			return ErrRequestCanceled
+		case <-expired:
+			return timedOut()
+		}
+
+		if err := c.acquire(req, expired); err != nil {
+			if err == (timeoutError{}) {
+				return timedOut()
+			}
+
 			return err
 		}
+		held = true
+	}
+
+	defer resp.Body.Close()
+
+	for _, fn := range cfg.interceptors {
+		if err := fn(resp); err != nil {
+			return err
+		}
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return readError(resp)
+	}
+
+	if v == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+
+	if !cfg.audit {
+		return json.NewDecoder(resp.Body).Decode(v)
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		return err
+	}
+
+	orphans, err := auditIncludes(body)
+	if err != nil {
+		return err
+	}
 
-		return errs
+	if len(orphans) > 0 {
+		return UnreferencedIncludesError{Items: orphans}
 	}
 
-	return json.NewDecoder(resp.Body).Decode(v)
+	return nil
 }
@@ -1,9 +1,15 @@
 package patreon
 
 import (
+	"errors"
+	"fmt"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"golang.org/x/oauth2"
@@ -37,7 +43,85 @@ func TestBuildURL(t *testing.T) {
 	)
 
 	require.NoError(t, err)
-	require.Equal(t, "https://api.patreon.com/path?fields%5Bpledge%5D=total_historical_amount_cents%2Cunread_count&include=patron%2Creward%2Ccreator&page%5Bcount%5D=10&page%5Bcursor%5D=123", url)
+	require.Equal(t, "https://api.patreon.com/path?fields%5Bpledge%5D=total_historical_amount_cents%2Cunread_count&include=creator%2Cpatron%2Creward&page%5Bcount%5D=10&page%5Bcursor%5D=123", url)
+}
+
+func TestWithBaseURL(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/oauth2/api/current_user", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, currentUserResp)
+	})
+
+	client := NewClient(nil, WithBaseURL(server.URL+"/"))
+
+	resp, err := client.FetchUser()
+	require.NoError(t, err)
+	require.Equal(t, "3232132131", resp.Data.ID)
+}
+
+func TestWithBaseURLInvalid(t *testing.T) {
+	for _, addr := range []string{"", "api.patreon.com", "/oauth2/api", "://api.patreon.com"} {
+		client := NewClient(nil, WithBaseURL(addr))
+
+		_, err := client.FetchUser()
+		require.Error(t, err, addr)
+	}
+}
+
+func TestBuildURLSortAndFilter(t *testing.T) {
+	client := NewClient(nil)
+
+	addr, err := client.buildURL("/path",
+		WithSort("-created_at"),
+		WithFilter("patron_status", "active_patron"),
+		WithSort("amount_cents"),
+		WithFilter("currency", "USD"),
+		WithIncludes("patron"),
+		WithPageSize(10),
+	)
+
+	require.NoError(t, err)
+	require.Equal(t, "https://api.patreon.com/path?include=patron&filter%5Bcurrency%5D=USD&filter%5Bpatron_status%5D=active_patron&sort=-created_at%2Camount_cents&page%5Bcount%5D=10", addr)
+}
+
+func TestBuildURLStableOrder(t *testing.T) {
+	client := NewClient(nil)
+
+	first, err := client.buildURL("/path",
+		WithFields("user", "full_name", "email"),
+		WithFields("pledge", "total_historical_amount_cents", "amount_cents"),
+		WithIncludes("reward", "patron"),
+	)
+	require.NoError(t, err)
+
+	second, err := client.buildURL("/path",
+		WithIncludes("patron", "reward"),
+		WithFields("pledge", "amount_cents", "total_historical_amount_cents"),
+		WithFields("user", "email", "full_name"),
+	)
+	require.NoError(t, err)
+
+	require.Equal(t, first, second)
+	require.Equal(t, "https://api.patreon.com/path?fields%5Bpledge%5D=amount_cents%2Ctotal_historical_amount_cents&fields%5Buser%5D=email%2Cfull_name&include=patron%2Creward", first)
+}
+
+func TestBuildURLCursorEncoding(t *testing.T) {
+	client := NewClient(nil)
+
+	for _, cursor := range []string{"a+b", "a/b", "a=b", "/w8+abc=="} {
+		addr, err := client.buildURL("/path", WithCursor(cursor))
+		require.NoError(t, err)
+
+		u, err := url.Parse(addr)
+		require.NoError(t, err)
+		require.Equal(t, cursor, u.Query().Get("page[cursor]"))
+	}
+
+	addr, err := client.buildURL("/path", WithCursor("/w8+abc=="))
+	require.NoError(t, err)
+	require.Equal(t, "https://api.patreon.com/path?page%5Bcursor%5D=%2Fw8%2Babc%3D%3D", addr)
 }
 
 func TestBuildURLWithInvalidPath(t *testing.T) {
@@ -53,3 +137,198 @@ func TestClient(t *testing.T) {
 	client := NewClient(tc)
 	require.Equal(t, tc, client.Client())
 }
+
+func TestResponseInterceptor(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/oauth2/api/current_user", func(writer http.ResponseWriter, request *http.Request) {
+		writer.Header().Set("X-Test", "123")
+		fmt.Fprint(writer, currentUserResp)
+	})
+
+	header := ""
+	_, err := client.FetchUser(WithResponseInterceptor(func(resp *http.Response) error {
+		header = resp.Header.Get("X-Test")
+		return nil
+	}))
+	require.NoError(t, err)
+	require.Equal(t, "123", header)
+
+	_, err = client.FetchUser(WithResponseInterceptor(func(resp *http.Response) error {
+		return errors.New("rejected")
+	}))
+	require.Error(t, err)
+	require.Equal(t, "rejected", err.Error())
+}
+
+func TestRequestTimeout(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/oauth2/api/current_user", func(writer http.ResponseWriter, request *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+		fmt.Fprint(writer, currentUserResp)
+	})
+
+	_, err := client.FetchUser(WithRequestTimeout(10 * time.Millisecond))
+	require.Error(t, err)
+
+	reqErr, ok := err.(RequestError)
+	require.True(t, ok)
+	require.True(t, reqErr.Elapsed >= 10*time.Millisecond)
+	require.True(t, reqErr.Timeout())
+
+	_, ok = reqErr.Unwrap().(*url.Error)
+	require.True(t, ok)
+
+	netErr, ok := err.(net.Error)
+	require.True(t, ok)
+	require.True(t, netErr.Timeout())
+	require.Equal(t, time.Duration(0), client.Client().Timeout)
+
+	_, err = client.FetchUser(WithRequestTimeout(time.Second))
+	require.NoError(t, err)
+}
+
+func TestDo(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/api/custom", func(writer http.ResponseWriter, request *http.Request) {
+		require.Equal(t, "POST", request.Method)
+		require.Equal(t, "application/json", request.Header.Get("Content-Type"))
+
+		writer.WriteHeader(http.StatusCreated)
+		fmt.Fprint(writer, `{"data": {"id": "1", "type": "custom"}}`)
+	})
+
+	req, err := http.NewRequest("POST", server.URL+"/api/custom", nil)
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp := struct {
+		Data Data `json:"data"`
+	}{}
+
+	err = client.Do(req, &resp)
+	require.NoError(t, err)
+	require.Equal(t, "1", resp.Data.ID)
+	require.Equal(t, "custom", resp.Data.Type)
+}
+
+func TestDoErrorResponse(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/api/custom", func(writer http.ResponseWriter, request *http.Request) {
+		writer.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(writer, errorResp)
+	})
+
+	req, err := http.NewRequest("GET", server.URL+"/api/custom", nil)
+	require.NoError(t, err)
+
+	err = client.Do(req, nil)
+	require.Error(t, err)
+
+	_, ok := err.(ErrorResponse)
+	require.True(t, ok)
+}
+
+func TestRequestErrorConnectionRefused(t *testing.T) {
+	setup()
+	teardown()
+
+	_, err := client.FetchUser()
+	require.Error(t, err)
+
+	reqErr, ok := err.(RequestError)
+	require.True(t, ok)
+	require.NotNil(t, reqErr.Err)
+	require.True(t, reqErr.Elapsed < time.Second)
+	require.False(t, reqErr.Timeout())
+
+	_, ok = reqErr.Unwrap().(*url.Error)
+	require.True(t, ok)
+}
+
+func TestWithClock(t *testing.T) {
+	now := time.Date(2017, 6, 20, 23, 21, 34, 0, time.UTC)
+	clock := func() time.Time {
+		now = now.Add(5 * time.Second)
+		return now
+	}
+
+	server := httptest.NewServer(http.NewServeMux())
+	server.Close()
+
+	client := NewClient(nil, WithClock(clock))
+	client.baseURL = server.URL
+
+	_, err := client.FetchUser()
+	require.Error(t, err)
+
+	reqErr, ok := err.(RequestError)
+	require.True(t, ok)
+	require.Equal(t, 5*time.Second, reqErr.Elapsed)
+}
+
+func TestWithMaxInFlight(t *testing.T) {
+	setup()
+	defer teardown()
+
+	client = NewClient(nil, WithMaxInFlight(2))
+	client.baseURL = server.URL
+
+	var (
+		mu       sync.Mutex
+		current  int
+		maxCount int
+	)
+
+	mux.HandleFunc("/oauth2/api/current_user", func(writer http.ResponseWriter, request *http.Request) {
+		mu.Lock()
+		current++
+		if current > maxCount {
+			maxCount = current
+		}
+		mu.Unlock()
+
+		time.Sleep(20 * time.Millisecond)
+
+		mu.Lock()
+		current--
+		mu.Unlock()
+
+		fmt.Fprint(writer, currentUserResp)
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := client.FetchUser()
+			require.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	require.Equal(t, 2, maxCount)
+}
+
+func TestWithMaxInFlightCancel(t *testing.T) {
+	client := NewClient(nil, WithMaxInFlight(1))
+	client.inFlight <- struct{}{}
+
+	cancel := make(chan struct{})
+	close(cancel)
+
+	req, err := http.NewRequest("GET", "http://localhost/", nil)
+	require.NoError(t, err)
+	req.Cancel = cancel
+
+	err = client.Do(req, nil)
+	require.Equal(t, ErrRequestCanceled, err)
+}
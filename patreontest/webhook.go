@@ -0,0 +1,16 @@
+// Package patreontest provides utilities for testing code that uses the patreon package.
+package patreontest
+
+import (
+	"crypto/hmac"
+	"crypto/md5"
+	"encoding/hex"
+)
+
+// SignWebhook returns the signature Patreon would send in X-Patreon-Signature header for the given message body.
+// The result passes patreon.VerifyWebhookSignature with the same secret.
+func SignWebhook(body []byte, secret string) string {
+	hash := hmac.New(md5.New, []byte(secret))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -0,0 +1,11 @@
+package patreontest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignWebhook(t *testing.T) {
+	require.Equal(t, "f592f6b389ea438135a5291fb0ae32a6", SignWebhook([]byte(`{"data":{}}`), "secret"))
+}
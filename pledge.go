@@ -29,6 +29,36 @@ type Pledge struct {
 	} `json:"relationships"`
 }
 
+// IsActive reports whether the pledge is neither declined nor paused.
+func (p *Pledge) IsActive() bool {
+	if p.Attributes.IsPaused != nil && *p.Attributes.IsPaused {
+		return false
+	}
+
+	return !p.Attributes.DeclinedSince.Valid
+}
+
+// LifetimeSupport returns the total amount (in cents) the patron has paid to the campaign.
+// The attribute is optional and must be requested with WithFields("pledge", "total_historical_amount_cents"),
+// otherwise 0 is returned.
+func (p *Pledge) LifetimeSupport() int64 {
+	if p.Attributes.TotalHistoricalAmountCents == nil {
+		return 0
+	}
+
+	return int64(*p.Attributes.TotalHistoricalAmountCents)
+}
+
+// AccessResult describes the user's entitlements to the campaign, see Client.CheckAccess.
+type AccessResult struct {
+	// IsActivePatron is true if the user has an active (neither declined nor paused) pledge to the campaign
+	IsActivePatron bool
+	// EntitledTierIDs lists IDs of the rewards (tiers) the user is entitled to
+	EntitledTierIDs []string
+	// EntitledAmountCents is the amount of the user's active pledge
+	EntitledAmountCents int64
+}
+
 // PledgeResponse wraps Patreon's pledges API response
 type PledgeResponse struct {
 	Data     []Pledge `json:"data"`
@@ -41,3 +71,34 @@ type PledgeResponse struct {
 		Count int `json:"count"`
 	} `json:"meta"`
 }
+
+// NextCursor returns the cursor of the next page extracted from Links.Next, or empty string if this is the last page.
+// Meta.Count holds the total number of pledges.
+func (r *PledgeResponse) NextCursor() string {
+	if r.Links.Next == "" {
+		return ""
+	}
+
+	return getOptions(WithCursor(r.Links.Next)).cursor
+}
+
+// Patron returns the included user who made the pledge, or nil if the patron wasn't included.
+func (r *PledgeResponse) Patron(pledge *Pledge) *User {
+	if pledge.Relationships.Patron == nil {
+		return nil
+	}
+
+	return r.Included.User(pledge.Relationships.Patron.Data.ID)
+}
+
+// PatronEmail returns the email of the user who made the pledge.
+// The patron must be included (see PledgeDefaultRelations) and the token needs 'pledges-to-me' scope,
+// empty string is returned otherwise.
+func (r *PledgeResponse) PatronEmail(pledge *Pledge) string {
+	user := r.Patron(pledge)
+	if user == nil {
+		return ""
+	}
+
+	return user.Attributes.Email
+}
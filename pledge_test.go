@@ -1,8 +1,10 @@
 package patreon
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -23,6 +25,7 @@ func TestFetchPledges(t *testing.T) {
 
 	require.NotEmpty(t, resp.Links.First)
 	require.NotEmpty(t, resp.Links.Next)
+	require.Equal(t, "2017-07-03T23:25:08.519452+00:00", resp.NextCursor())
 
 	// Attributes
 
@@ -52,6 +55,11 @@ func TestFetchPledges(t *testing.T) {
 	require.Equal(t, "21321321321", reward.Data.ID)
 	require.Equal(t, "reward", reward.Data.Type)
 	require.Equal(t, "https://www.patreon.com/api/rewards/21321321321", reward.Links.Related)
+
+	// Optional properties
+
+	require.Equal(t, int64(0), resp.Data[0].LifetimeSupport())
+	require.Equal(t, int64(100), resp.Data[1].LifetimeSupport())
 }
 
 const fetchPledgesResp = `
@@ -115,3 +123,318 @@ const fetchPledgesResp = `
     }
 }
 `
+
+func TestFetchPledgesEmptyCampaignID(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/oauth2/api/campaigns//pledges", func(writer http.ResponseWriter, request *http.Request) {
+		t.Fatal("unexpected request")
+	})
+
+	_, err := client.FetchPledges("")
+	require.Equal(t, ErrEmptyCampaignID, err)
+}
+
+func TestFetchPledgesEmptyOnNotFound(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/oauth2/api/campaigns/123/pledges", func(writer http.ResponseWriter, request *http.Request) {
+		writer.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(writer, `{"errors": [{"code": 4, "code_name": "NotFound", "detail": "Campaign not found", "status": "404"}]}`)
+	})
+
+	_, err := client.FetchPledges("123")
+	require.Error(t, err)
+
+	resp, err := client.FetchPledges("123", WithEmptyOnNotFound())
+	require.NoError(t, err)
+	require.Empty(t, resp.Data)
+
+	resp, err = client.FetchAllActivePledges("123", WithEmptyOnNotFound())
+	require.NoError(t, err)
+	require.Empty(t, resp.Data)
+}
+
+func TestFetchAllActivePledges(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/oauth2/api/campaigns/123/pledges", func(writer http.ResponseWriter, request *http.Request) {
+		require.Equal(t, "patron,reward", request.URL.Query().Get("include"))
+		require.Contains(t, strings.Split(request.URL.Query().Get("fields[pledge]"), ","), "is_paused")
+
+		if request.URL.Query().Get("page[cursor]") == "" {
+			fmt.Fprint(writer, activePledgesFirstPage)
+		} else {
+			require.Equal(t, "2017-07-03T23:25:08+00:00", request.URL.Query().Get("page[cursor]"))
+			fmt.Fprint(writer, activePledgesLastPage)
+		}
+	})
+
+	resp, err := client.FetchAllActivePledges("123")
+	require.NoError(t, err)
+
+	require.Len(t, resp.Data, 2)
+	require.Equal(t, "1", resp.Data[0].ID)
+	require.Equal(t, "3", resp.Data[1].ID)
+	require.Equal(t, 4, resp.Meta.Count)
+	require.Empty(t, resp.Links.Next)
+	require.Empty(t, resp.NextCursor())
+
+	require.Len(t, resp.Included.Items, 2)
+	require.NotNil(t, resp.Included.find("user", "10"))
+	require.NotNil(t, resp.Included.find("user", "30"))
+}
+
+func TestFetchAllActivePledgesMaxPages(t *testing.T) {
+	setup()
+	defer teardown()
+
+	pages := 0
+	mux.HandleFunc("/oauth2/api/campaigns/123/pledges", func(writer http.ResponseWriter, request *http.Request) {
+		pages++
+		fmt.Fprint(writer, activePledgesFirstPage)
+	})
+
+	resp, err := client.FetchAllActivePledges("123", WithMaxPages(3))
+	require.Equal(t, ErrMaxPagesExceeded, err)
+	require.Equal(t, 3, pages)
+	require.Len(t, resp.Data, 3)
+}
+
+func TestFetchAllActivePledgesAuditIncludes(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/oauth2/api/campaigns/123/pledges", func(writer http.ResponseWriter, request *http.Request) {
+		if request.URL.Query().Get("page[cursor]") == "" {
+			fmt.Fprint(writer, activePledgesFirstPage)
+		} else {
+			fmt.Fprint(writer, activePledgesLastPage)
+		}
+	})
+
+	resp, err := client.FetchAllActivePledges("123", WithAuditIncludes())
+	require.Error(t, err)
+
+	// User 10 is included on the last page, but referenced only by pledges of the first one
+	auditErr, ok := err.(UnreferencedIncludesError)
+	require.True(t, ok)
+	require.Equal(t, []Data{{ID: "10", Type: "user"}}, auditErr.Items)
+
+	require.NotNil(t, resp)
+	require.Len(t, resp.Data, 2)
+	require.Len(t, resp.Included.Items, 2)
+
+	result, err := client.CheckAccess("123", "30", WithAuditIncludes())
+	require.Error(t, err)
+	require.NotNil(t, result)
+	require.True(t, result.IsActivePatron)
+}
+
+func TestFetchAllActivePledgesForCampaigns(t *testing.T) {
+	setup()
+	defer teardown()
+
+	for _, id := range []string{"1", "2"} {
+		mux.HandleFunc("/oauth2/api/campaigns/"+id+"/pledges", func(writer http.ResponseWriter, request *http.Request) {
+			fmt.Fprint(writer, activePledgesLastPage)
+		})
+	}
+
+	mux.HandleFunc("/oauth2/api/campaigns/3/pledges", func(writer http.ResponseWriter, request *http.Request) {
+		writer.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(writer, errorResp)
+	})
+
+	resp, err := client.FetchAllActivePledgesForCampaigns([]string{"1", "2", "3"})
+	require.Error(t, err)
+	require.Equal(t, "campaign 3: The server could not verify that you are authorized to access the URL requested.", err.Error())
+
+	errs, ok := err.(CampaignErrors)
+	require.True(t, ok)
+	require.Len(t, errs, 1)
+
+	require.Len(t, resp, 2)
+	require.Len(t, resp["1"].Data, 1)
+	require.Len(t, resp["2"].Data, 1)
+}
+
+func TestFetchAllActivePledgesForCampaignsPartial(t *testing.T) {
+	setup()
+	defer teardown()
+
+	for _, id := range []string{"1", "2"} {
+		mux.HandleFunc("/oauth2/api/campaigns/"+id+"/pledges", func(writer http.ResponseWriter, request *http.Request) {
+			if request.URL.Query().Get("page[cursor]") == "" {
+				fmt.Fprint(writer, activePledgesFirstPage)
+			} else {
+				fmt.Fprint(writer, activePledgesLastPage)
+			}
+		})
+	}
+
+	resp, err := client.FetchAllActivePledgesForCampaigns([]string{"1", "2"}, WithMaxPages(1))
+	require.Error(t, err)
+
+	errs, ok := err.(CampaignErrors)
+	require.True(t, ok)
+	require.Equal(t, ErrMaxPagesExceeded, errs["1"])
+	require.Equal(t, ErrMaxPagesExceeded, errs["2"])
+
+	require.Len(t, resp, 2)
+	require.Len(t, resp["1"].Data, 1)
+	require.Len(t, resp["2"].Data, 1)
+
+	resp, err = client.FetchAllActivePledgesForCampaigns([]string{"1", "2"}, WithAuditIncludes())
+	require.Error(t, err)
+
+	errs, ok = err.(CampaignErrors)
+	require.True(t, ok)
+	require.Len(t, errs, 2)
+	require.IsType(t, UnreferencedIncludesError{}, errs["1"])
+
+	require.Len(t, resp, 2)
+	require.Len(t, resp["1"].Data, 2)
+	require.Len(t, resp["2"].Data, 2)
+}
+
+func TestCampaignMRR(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/oauth2/api/campaigns/123/pledges", func(writer http.ResponseWriter, request *http.Request) {
+		if request.URL.Query().Get("page[cursor]") == "" {
+			fmt.Fprint(writer, activePledgesFirstPage)
+		} else {
+			fmt.Fprint(writer, activePledgesLastPage)
+		}
+	})
+
+	cents, currency, err := client.CampaignMRR("123")
+	require.NoError(t, err)
+	require.Equal(t, int64(350), cents)
+	require.Equal(t, "USD", currency)
+}
+
+func TestCampaignMRRMixedCurrencies(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/oauth2/api/campaigns/123/pledges", func(writer http.ResponseWriter, request *http.Request) {
+		fmt.Fprint(writer, `{"data": [
+			{"attributes": {"amount_cents": 100, "currency": "USD", "declined_since": null}, "id": "1", "type": "pledge"},
+			{"attributes": {"amount_cents": 100, "currency": "EUR", "declined_since": null}, "id": "2", "type": "pledge"}
+		]}`)
+	})
+
+	_, _, err := client.CampaignMRR("123")
+	require.Error(t, err)
+	require.Equal(t, "pledges are in different currencies: USD, EUR", err.Error())
+}
+
+func TestCheckAccess(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/oauth2/api/campaigns/123/pledges", func(writer http.ResponseWriter, request *http.Request) {
+		if request.URL.Query().Get("page[cursor]") == "" {
+			fmt.Fprint(writer, activePledgesFirstPage)
+		} else {
+			fmt.Fprint(writer, activePledgesLastPage)
+		}
+	})
+
+	// User 10 has an active pledge for reward 100 and a declined one for reward 200
+	result, err := client.CheckAccess("123", "10")
+	require.NoError(t, err)
+	require.True(t, result.IsActivePatron)
+	require.Equal(t, []string{"100"}, result.EntitledTierIDs)
+	require.Equal(t, int64(100), result.EntitledAmountCents)
+
+	// User 30 has an active pledge for reward 100 and a paused one for reward 200
+	result, err = client.CheckAccess("123", "30")
+	require.NoError(t, err)
+	require.True(t, result.IsActivePatron)
+	require.Equal(t, []string{"100"}, result.EntitledTierIDs)
+	require.Equal(t, int64(250), result.EntitledAmountCents)
+
+	result, err = client.CheckAccess("123", "99")
+	require.NoError(t, err)
+	require.False(t, result.IsActivePatron)
+	require.Empty(t, result.EntitledTierIDs)
+	require.Equal(t, int64(0), result.EntitledAmountCents)
+}
+
+func TestPledgePatronEmail(t *testing.T) {
+	resp := PledgeResponse{}
+	err := json.Unmarshal([]byte(activePledgesFirstPage), &resp)
+	require.NoError(t, err)
+
+	patron := resp.Patron(&resp.Data[0])
+	require.NotNil(t, patron)
+	require.Equal(t, "First", patron.Attributes.FullName)
+	require.Equal(t, "first@example.com", resp.PatronEmail(&resp.Data[0]))
+
+	resp.Included = Includes{}
+	require.Nil(t, resp.Patron(&resp.Data[0]))
+	require.Empty(t, resp.PatronEmail(&resp.Data[0]))
+
+	require.Empty(t, resp.PatronEmail(&Pledge{}))
+}
+
+const activePledgesFirstPage = `
+{
+    "data": [
+        {
+            "attributes": {"amount_cents": 100, "currency": "USD", "declined_since": null},
+            "id": "1",
+            "relationships": {"patron": {"data": {"id": "10", "type": "user"}}, "reward": {"data": {"id": "100", "type": "reward"}}},
+            "type": "pledge"
+        },
+        {
+            "attributes": {"amount_cents": 500, "currency": "USD", "declined_since": "2017-06-20T23:21:34+00:00"},
+            "id": "2",
+            "relationships": {"patron": {"data": {"id": "10", "type": "user"}}, "reward": {"data": {"id": "200", "type": "reward"}}},
+            "type": "pledge"
+        }
+    ],
+    "included": [
+        {"attributes": {"email": "first@example.com", "full_name": "First"}, "id": "10", "type": "user"}
+    ],
+    "links": {
+        "first": "https://www.patreon.com/api/oauth2/api/campaigns/123/pledges?page%5Bcount%5D=2",
+        "next": "https://www.patreon.com/api/oauth2/api/campaigns/123/pledges?page%5Bcount%5D=2&page%5Bcursor%5D=2017-07-03T23%3A25%3A08%2B00%3A00"
+    },
+    "meta": {"count": 4}
+}
+`
+
+const activePledgesLastPage = `
+{
+    "data": [
+        {
+            "attributes": {"amount_cents": 250, "currency": "USD", "declined_since": null},
+            "id": "3",
+            "relationships": {"patron": {"data": {"id": "30", "type": "user"}}, "reward": {"data": {"id": "100", "type": "reward"}}},
+            "type": "pledge"
+        },
+        {
+            "attributes": {"amount_cents": 900, "currency": "USD", "declined_since": null, "is_paused": true},
+            "id": "4",
+            "relationships": {"patron": {"data": {"id": "30", "type": "user"}}, "reward": {"data": {"id": "200", "type": "reward"}}},
+            "type": "pledge"
+        }
+    ],
+    "included": [
+        {"attributes": {"email": "first@example.com", "full_name": "First"}, "id": "10", "type": "user"},
+        {"attributes": {"email": "third@example.com", "full_name": "Third"}, "id": "30", "type": "user"}
+    ],
+    "links": {
+        "first": "https://www.patreon.com/api/oauth2/api/campaigns/123/pledges?page%5Bcount%5D=2"
+    },
+    "meta": {"count": 4}
+}
+`
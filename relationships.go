@@ -11,6 +11,16 @@ type Meta struct {
 	Count int `json:"count"`
 }
 
+// relationshipIDs returns the IDs of the linked resources.
+func relationshipIDs(data []Data) []string {
+	ids := make([]string, len(data))
+	for idx, d := range data {
+		ids[idx] = d.ID
+	}
+
+	return ids
+}
+
 // CategoriesRelationship represents 'categories' include.
 type CategoriesRelationship struct {
 	Data []Data `json:"data"`
@@ -0,0 +1,280 @@
+package patreon
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetry(t *testing.T) {
+	setup()
+	defer teardown()
+
+	client = NewClient(nil, WithRetry(3, time.Millisecond))
+	client.baseURL = server.URL
+
+	requests := 0
+	mux.HandleFunc("/oauth2/api/current_user", func(writer http.ResponseWriter, request *http.Request) {
+		requests++
+		switch requests {
+		case 1:
+			writer.WriteHeader(statusTooManyRequests)
+		case 2:
+			writer.WriteHeader(http.StatusBadGateway)
+		default:
+			fmt.Fprint(writer, currentUserResp)
+		}
+	})
+
+	resp, err := client.FetchUser()
+	require.NoError(t, err)
+	require.Equal(t, "3232132131", resp.Data.ID)
+	require.Equal(t, 3, requests)
+}
+
+func TestRetryExhausted(t *testing.T) {
+	setup()
+	defer teardown()
+
+	client = NewClient(nil, WithRetry(2, time.Millisecond))
+	client.baseURL = server.URL
+
+	requests := 0
+	mux.HandleFunc("/oauth2/api/current_user", func(writer http.ResponseWriter, request *http.Request) {
+		requests++
+		writer.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	_, err := client.FetchUser()
+	require.Error(t, err)
+	require.Equal(t, 3, requests)
+
+	apiErr, ok := err.(APIError)
+	require.True(t, ok)
+	require.Equal(t, http.StatusServiceUnavailable, apiErr.StatusCode)
+}
+
+func TestNoRetryByDefault(t *testing.T) {
+	setup()
+	defer teardown()
+
+	requests := 0
+	mux.HandleFunc("/oauth2/api/current_user", func(writer http.ResponseWriter, request *http.Request) {
+		requests++
+		writer.WriteHeader(statusTooManyRequests)
+	})
+
+	_, err := client.FetchUser()
+	require.Error(t, err)
+	require.Equal(t, 1, requests)
+}
+
+func TestNoRetryOnClientError(t *testing.T) {
+	setup()
+	defer teardown()
+
+	client = NewClient(nil, WithRetry(3, time.Millisecond))
+	client.baseURL = server.URL
+
+	requests := 0
+	mux.HandleFunc("/oauth2/api/current_user", func(writer http.ResponseWriter, request *http.Request) {
+		requests++
+		writer.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(writer, errorResp)
+	})
+
+	_, err := client.FetchUser()
+	require.Error(t, err)
+	require.Equal(t, 1, requests)
+}
+
+func TestRetryErrorClassifier(t *testing.T) {
+	setup()
+	defer teardown()
+
+	client = NewClient(nil,
+		WithRetry(3, time.Millisecond),
+		WithErrorClassifier(func(resp *http.Response) bool {
+			return resp.StatusCode == http.StatusNotFound
+		}))
+	client.baseURL = server.URL
+
+	requests := 0
+	mux.HandleFunc("/oauth2/api/current_user", func(writer http.ResponseWriter, request *http.Request) {
+		requests++
+		if requests == 1 {
+			writer.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		writer.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	// 404 is retried, while 503 is not anymore
+	_, err := client.FetchUser()
+	require.Error(t, err)
+	require.Equal(t, 2, requests)
+
+	apiErr, ok := err.(APIError)
+	require.True(t, ok)
+	require.Equal(t, http.StatusServiceUnavailable, apiErr.StatusCode)
+}
+
+func TestRetryCancel(t *testing.T) {
+	setup()
+	defer teardown()
+
+	client = NewClient(nil, WithRetry(3, time.Hour))
+	client.baseURL = server.URL
+
+	mux.HandleFunc("/oauth2/api/current_user", func(writer http.ResponseWriter, request *http.Request) {
+		writer.WriteHeader(statusTooManyRequests)
+	})
+
+	req, err := http.NewRequest("GET", server.URL+"/oauth2/api/current_user", nil)
+	require.NoError(t, err)
+
+	cancel := make(chan struct{})
+	req.Cancel = cancel
+	time.AfterFunc(10*time.Millisecond, func() { close(cancel) })
+
+	err = client.Do(req, nil)
+	require.Equal(t, ErrRequestCanceled, err)
+}
+
+func TestRetryReleasesInFlightSlot(t *testing.T) {
+	setup()
+	defer teardown()
+
+	client = NewClient(nil, WithMaxInFlight(1), WithRetry(1, 500*time.Millisecond))
+	client.baseURL = server.URL
+
+	var once sync.Once
+	limited := make(chan struct{})
+	mux.HandleFunc("/oauth2/api/current_user", func(writer http.ResponseWriter, request *http.Request) {
+		first := false
+		once.Do(func() { first = true })
+
+		if first {
+			writer.WriteHeader(statusTooManyRequests)
+			close(limited)
+			return
+		}
+
+		fmt.Fprint(writer, currentUserResp)
+	})
+
+	mux.HandleFunc("/oauth2/api/current_user/campaigns", func(writer http.ResponseWriter, request *http.Request) {
+		fmt.Fprint(writer, fetchCampaignResp)
+	})
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := client.FetchUser()
+		done <- err
+	}()
+
+	// While the first request waits to be retried, the other one gets the slot
+	<-limited
+	start := time.Now()
+	_, err := client.FetchCampaign()
+	require.NoError(t, err)
+	require.True(t, time.Since(start) < 400*time.Millisecond)
+
+	require.NoError(t, <-done)
+}
+
+// failingTransport responds with 503 Service Unavailable once and fails to connect afterwards
+type failingTransport struct {
+	calls int
+}
+
+func (f *failingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	f.calls++
+	if f.calls > 1 {
+		return nil, errors.New("connection refused")
+	}
+
+	return &http.Response{
+		StatusCode: http.StatusServiceUnavailable,
+		Header:     http.Header{},
+		Body:       ioutil.NopCloser(strings.NewReader("")),
+		Request:    req,
+	}, nil
+}
+
+func TestRetryRequestErrorElapsed(t *testing.T) {
+	client := NewClient(&http.Client{Transport: &failingTransport{}}, WithRetry(1, 100*time.Millisecond))
+
+	_, err := client.FetchUser()
+	require.Error(t, err)
+
+	// Time spent on the first attempt and waiting for the retry is included
+	reqErr, ok := err.(RequestError)
+	require.True(t, ok)
+	require.True(t, reqErr.Elapsed >= 100*time.Millisecond)
+}
+
+func TestRetryRequestTimeout(t *testing.T) {
+	setup()
+	defer teardown()
+
+	client = NewClient(nil, WithRetry(2, 300*time.Millisecond))
+	client.baseURL = server.URL
+
+	mux.HandleFunc("/oauth2/api/current_user", func(writer http.ResponseWriter, request *http.Request) {
+		writer.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	// The timeout covers waiting between attempts
+	start := time.Now()
+	_, err := client.FetchUser(WithRequestTimeout(50 * time.Millisecond))
+	require.True(t, time.Since(start) < 250*time.Millisecond)
+
+	reqErr, ok := err.(RequestError)
+	require.True(t, ok)
+	require.True(t, reqErr.Timeout())
+	require.True(t, reqErr.Elapsed >= 50*time.Millisecond)
+}
+
+func TestInFlightRequestTimeout(t *testing.T) {
+	client := NewClient(nil, WithMaxInFlight(1))
+	client.inFlight <- struct{}{}
+
+	_, err := client.FetchUser(WithRequestTimeout(20 * time.Millisecond))
+
+	reqErr, ok := err.(RequestError)
+	require.True(t, ok)
+	require.True(t, reqErr.Timeout())
+}
+
+func TestRetryDelay(t *testing.T) {
+	now := time.Date(2017, 6, 20, 23, 21, 34, 0, time.UTC)
+	client := NewClient(nil,
+		WithRetry(3, 100*time.Millisecond),
+		WithClock(func() time.Time { return now }))
+
+	resp := &http.Response{Header: http.Header{}}
+	require.Equal(t, 100*time.Millisecond, client.retryDelay(resp, 0))
+	require.Equal(t, 200*time.Millisecond, client.retryDelay(resp, 1))
+	require.Equal(t, 400*time.Millisecond, client.retryDelay(resp, 2))
+
+	require.Equal(t, maxRetryDelay, client.retryDelay(resp, 20))
+	require.Equal(t, maxRetryDelay, client.retryDelay(resp, 100))
+
+	resp.Header.Set("Retry-After", "7")
+	require.Equal(t, 7*time.Second, client.retryDelay(resp, 0))
+
+	resp.Header.Set("Retry-After", now.Add(30*time.Second).Format(http.TimeFormat))
+	require.Equal(t, 30*time.Second, client.retryDelay(resp, 0))
+
+	resp.Header.Set("Retry-After", now.Add(-time.Second).Format(http.TimeFormat))
+	require.Equal(t, time.Duration(0), client.retryDelay(resp, 0))
+}
@@ -0,0 +1,78 @@
+package patreon
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/oauth2"
+)
+
+// OAuth2 scopes of API v1 (see https://docs.patreon.com/#scopes).
+// Scopes introduced by API v2 (such as 'identity' or 'campaigns.members') don't grant access to the endpoints of this client.
+const (
+	// ScopeUsers gives read access to the user's profile info (see FetchUser).
+	ScopeUsers = "users"
+	// ScopePledgesToMe gives read access to the pledges to the user's campaign (see FetchPledges).
+	ScopePledgesToMe = "pledges-to-me"
+	// ScopeMyCampaign gives read access to the user's campaign, its rewards and goals (see FetchCampaign).
+	ScopeMyCampaign = "my-campaign"
+)
+
+// Scopes joins scopes into a space-delimited list, as expected by 'scope' parameter of the authorization request.
+func Scopes(s ...string) string {
+	return strings.Join(s, " ")
+}
+
+// ScopeError is returned when an OAuth2 token hasn't been granted some of the required scopes.
+type ScopeError struct {
+	Missing []string
+}
+
+func (e ScopeError) Error() string {
+	return fmt.Sprintf("token is missing required scopes: %s", strings.Join(e.Missing, ", "))
+}
+
+// ErrUnknownScopes is returned when a token carries no information about granted scopes. It's the case for tokens
+// restored from stored values, and the token endpoint may omit 'scope' when it's identical to the requested one.
+var ErrUnknownScopes = errors.New("token has no information about granted scopes")
+
+// RequireScopes verifies that the token has been granted all of the given scopes.
+// Granted scopes are read from the space-delimited 'scope' field of the token response (see https://tools.ietf.org/html/rfc6749#section-5.1),
+// which is only available on tokens obtained from the token endpoint, ErrUnknownScopes is returned otherwise.
+// If any scope is missing, a ScopeError listing them is returned.
+func RequireScopes(tok *oauth2.Token, scopes ...string) error {
+	var granted string
+	if tok != nil {
+		granted, _ = tok.Extra("scope").(string)
+	}
+
+	return RequireGrantedScopes(granted, scopes...)
+}
+
+// RequireGrantedScopes verifies that the space-delimited list of granted scopes (such as TokenResponse.Scope)
+// contains all of the given scopes. ErrUnknownScopes is returned for an empty list, otherwise the same as RequireScopes.
+func RequireGrantedScopes(granted string, scopes ...string) error {
+	fields := strings.Fields(granted)
+	if len(fields) == 0 {
+		return ErrUnknownScopes
+	}
+
+	set := make(map[string]bool, len(fields))
+	for _, scope := range fields {
+		set[scope] = true
+	}
+
+	var missing []string
+	for _, scope := range scopes {
+		if !set[scope] {
+			missing = append(missing, scope)
+		}
+	}
+
+	if len(missing) > 0 {
+		return ScopeError{Missing: missing}
+	}
+
+	return nil
+}
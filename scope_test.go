@@ -0,0 +1,49 @@
+package patreon
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+)
+
+func TestRequireScopes(t *testing.T) {
+	tok := (&oauth2.Token{AccessToken: "123"}).WithExtra(map[string]interface{}{
+		"scope": "users pledges-to-me",
+	})
+
+	require.NoError(t, RequireScopes(tok, "users"))
+	require.NoError(t, RequireScopes(tok, "users", "pledges-to-me"))
+
+	err := RequireScopes(tok, "users", "my-campaign")
+	require.Error(t, err)
+	require.Equal(t, "token is missing required scopes: my-campaign", err.Error())
+
+	scopeErr, ok := err.(ScopeError)
+	require.True(t, ok)
+	require.Equal(t, []string{"my-campaign"}, scopeErr.Missing)
+}
+
+func TestRequireScopesWithoutScopeField(t *testing.T) {
+	// Token restored from stored values
+	err := RequireScopes(&oauth2.Token{AccessToken: "123"}, "users", "my-campaign")
+	require.Equal(t, ErrUnknownScopes, err)
+
+	require.Equal(t, ErrUnknownScopes, RequireScopes(nil, "users"))
+}
+
+func TestRequireGrantedScopes(t *testing.T) {
+	resp := TokenResponse{Scope: "users pledges-to-me"}
+	require.NoError(t, RequireGrantedScopes(resp.Scope, ScopeUsers, ScopePledgesToMe))
+
+	err := RequireGrantedScopes(resp.Scope, ScopeMyCampaign)
+	require.Equal(t, ScopeError{Missing: []string{"my-campaign"}}, err)
+
+	require.Equal(t, ErrUnknownScopes, RequireGrantedScopes("", ScopeUsers))
+}
+
+func TestScopes(t *testing.T) {
+	require.Equal(t, "users pledges-to-me my-campaign", Scopes(ScopeUsers, ScopePledgesToMe, ScopeMyCampaign))
+	require.Equal(t, "users", Scopes(ScopeUsers))
+	require.Equal(t, "", Scopes())
+}
@@ -1,5 +1,7 @@
 package patreon
 
+import "net/url"
+
 // UserDefaultRelations specifies default includes for User.
 const UserDefaultRelations = "campaign,pledges"
 
@@ -32,11 +34,37 @@ type User struct {
 		DiscordId         string            `json:"discord_id"`
 		SocialConnections SocialConnections `json:"social_connections"`
 	} `json:"attributes"`
+	// Relationships are nil when API didn't return them (they weren't included or the token lacks scope for them),
+	// while a returned relationship with empty data means the user has no related resources.
 	Relationships struct {
-		Pledges *PledgesRelationship `json:"pledges,omitempty"`
+		Pledges  *PledgesRelationship  `json:"pledges,omitempty"`
+		Campaign *CampaignRelationship `json:"campaign,omitempty"`
 	} `json:"relationships"`
 }
 
+// DiscordUserID returns the ID of the Discord account connected to the user, or empty string if there is none.
+func (u *User) DiscordUserID() string {
+	if id := u.Attributes.SocialConnections.Discord.UserID; id != "" {
+		return id
+	}
+
+	return u.Attributes.DiscordId
+}
+
+// ProfileURL returns the URL of the user's Patreon page.
+// If API didn't return it, the URL is built from the vanity name or, as the last resort, from the user ID.
+func (u *User) ProfileURL() string {
+	if u.Attributes.URL != "" {
+		return u.Attributes.URL
+	}
+
+	if u.Attributes.Vanity != "" {
+		return "https://www.patreon.com/" + u.Attributes.Vanity
+	}
+
+	return "https://www.patreon.com/user?u=" + url.QueryEscape(u.ID)
+}
+
 // UserResponse wraps Patreon's fetch user API response
 type UserResponse struct {
 	Data     User     `json:"data"`
@@ -46,6 +74,7 @@ type UserResponse struct {
 	} `json:"links"`
 }
 
+// SocialConnections represents user's accounts connected on other platforms.
 type SocialConnections struct {
 	DeviantArt SocialConnection `json:"deviantart"`
 	Discord    SocialConnection `json:"discord"`
@@ -56,6 +85,7 @@ type SocialConnections struct {
 	YouTube    SocialConnection `json:"youtube"`
 }
 
+// SocialConnection represents a connected account.
 type SocialConnection struct {
 	Url    string `json:"url"`
 	UserID string `json:"user_id"`
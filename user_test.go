@@ -1,6 +1,7 @@
 package patreon
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"testing"
@@ -25,6 +26,7 @@ func TestFetchUser(t *testing.T) {
 	// Attributes
 
 	attrs := resp.Data.Attributes
+	require.Equal(t, "Podsync author", attrs.About)
 	require.Equal(t, "max@gmail.com", attrs.Email)
 	require.Equal(t, "max", attrs.Facebook)
 	require.Equal(t, "1312321312", attrs.FacebookId)
@@ -50,13 +52,87 @@ func TestFetchUser(t *testing.T) {
 	require.Len(t, pledges.Data, 1)
 	require.Equal(t, "2444714", pledges.Data[0].ID)
 	require.Equal(t, "pledge", pledges.Data[0].Type)
+	require.Nil(t, resp.Data.Relationships.Campaign)
+}
+
+func TestUserRelationshipsAbsentVsEmpty(t *testing.T) {
+	user := User{}
+	err := json.Unmarshal([]byte(`{
+		"id": "1",
+		"relationships": {
+			"campaign": {"data": {"id": "70261", "type": "campaign"}},
+			"pledges": {"data": []}
+		},
+		"type": "user"
+	}`), &user)
+	require.NoError(t, err)
+
+	require.NotNil(t, user.Relationships.Campaign)
+	require.Equal(t, "70261", user.Relationships.Campaign.Data.ID)
+	require.NotNil(t, user.Relationships.Pledges)
+	require.Empty(t, user.Relationships.Pledges.Data)
+
+	user = User{}
+	err = json.Unmarshal([]byte(`{"id": "1", "relationships": {}, "type": "user"}`), &user)
+	require.NoError(t, err)
+	require.Nil(t, user.Relationships.Campaign)
+	require.Nil(t, user.Relationships.Pledges)
+}
+
+func TestFetchUserIgnoresEmptyOnNotFound(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/oauth2/api/current_user", func(writer http.ResponseWriter, request *http.Request) {
+		writer.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(writer, `{"errors": [{"code": 4, "code_name": "NotFound", "detail": "User not found", "status": "404"}]}`)
+	})
+
+	_, err := client.FetchUser(WithEmptyOnNotFound())
+	require.Error(t, err)
+
+	errResp, ok := err.(ErrorResponse)
+	require.True(t, ok)
+	require.Equal(t, http.StatusNotFound, errResp.StatusCode)
+}
+
+func TestUserDiscordUserID(t *testing.T) {
+	user := User{}
+	err := json.Unmarshal([]byte(`{
+		"attributes": {
+			"discord_id": "111",
+			"social_connections": {
+				"discord": {"url": null, "user_id": "222"}
+			}
+		},
+		"id": "1",
+		"type": "user"
+	}`), &user)
+	require.NoError(t, err)
+	require.Equal(t, "222", user.DiscordUserID())
+
+	user.Attributes.SocialConnections.Discord.UserID = ""
+	require.Equal(t, "111", user.DiscordUserID())
+
+	require.Empty(t, (&User{}).DiscordUserID())
+}
+
+func TestUserProfileURL(t *testing.T) {
+	user := User{ID: "3232132131"}
+	require.Equal(t, "https://www.patreon.com/user?u=3232132131", user.ProfileURL())
+
+	user.Attributes.Vanity = "podsync"
+	require.Equal(t, "https://www.patreon.com/podsync", user.ProfileURL())
+
+	user.Attributes.URL = "https://www.patreon.com/pod_sync"
+	require.Equal(t, "https://www.patreon.com/pod_sync", user.ProfileURL())
 }
 
 const currentUserResp = `
 {
     "data": {
         "attributes": {
-            "about": "",
+            "about": "Podsync author",
             "created": "2016-02-02T19:56:14+00:00",
             "discord_id": null,
             "email": "max@gmail.com",
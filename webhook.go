@@ -4,6 +4,11 @@ import (
 	"crypto/hmac"
 	"crypto/md5"
 	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
 )
 
 const (
@@ -25,19 +30,132 @@ const (
 	HeaderSignature = "X-Patreon-Signature"
 )
 
+// ErrInvalidSignature is returned by ReceiveWebhook when the message signature doesn't match.
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// WebhookReadError is returned by ReceiveWebhook when the request body can't be read.
+type WebhookReadError struct {
+	Err error
+}
+
+func (e WebhookReadError) Error() string {
+	return fmt.Sprintf("failed to read webhook body: %v", e.Err)
+}
+
+// WebhookParseError is returned by ReceiveWebhook when the message can't be parsed.
+type WebhookParseError struct {
+	Err error
+}
+
+func (e WebhookParseError) Error() string {
+	return fmt.Sprintf("failed to parse webhook: %v", e.Err)
+}
+
+// AllWebhookEvents returns all known webhook event types.
+// Keep in sync with the Event* constants above.
+func AllWebhookEvents() []string {
+	return []string{
+		EventCreatePledge,
+		EventUpdatePledge,
+		EventDeletePledge,
+	}
+}
+
 type WebhookPledge struct {
-	Data Pledge `json:"data"`
+	Data     Pledge   `json:"data"`
+	Included Includes `json:"included"`
 }
 
-// VerifySignature verifies the sender of the message
-func VerifySignature(message []byte, secret string, signature string) (bool, error) {
-	hash := hmac.New(md5.New, []byte(secret))
-	if _, err := hash.Write(message); err != nil {
-		return false, err
+// Patron returns the included user who made the pledge, or nil if it's not included.
+func (w *WebhookPledge) Patron() *User {
+	if w.Data.Relationships.Patron == nil {
+		return nil
 	}
 
-	sum := hash.Sum(nil)
-	expectedSignature := hex.EncodeToString(sum)
+	return w.Included.User(w.Data.Relationships.Patron.Data.ID)
+}
+
+// Reward returns the included reward (tier) of the pledge, or nil if it's not included.
+func (w *WebhookPledge) Reward() *Reward {
+	if w.Data.Relationships.Reward == nil {
+		return nil
+	}
 
-	return expectedSignature == signature, nil
+	return w.Included.Reward(w.Data.Relationships.Reward.Data.ID)
+}
+
+// WebhookEvent represents a verified webhook message.
+type WebhookEvent struct {
+	// Type is the event type taken from X-Patreon-Event header
+	Type string
+	// Pledge is set for pledges:* events
+	Pledge *WebhookPledge
+	// Body is the raw (verified) message, e.g. to archive the exact signed payload
+	Body []byte
+}
+
+// ReceiveWebhook reads the webhook request, verifies its signature and parses the message depending on the event type.
+// It returns ErrInvalidSignature if the signature doesn't match, WebhookReadError if the body can't be read and
+// WebhookParseError if the message is malformed or has unsupported event type.
+func ReceiveWebhook(r *http.Request, secret string) (*WebhookEvent, error) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return nil, WebhookReadError{Err: err}
+	}
+
+	if !VerifyWebhookSignature(body, r.Header.Get(HeaderSignature), secret) {
+		return nil, ErrInvalidSignature
+	}
+
+	event, err := ParseWebhookPayload(r.Header.Get(HeaderEventType), body)
+	if err != nil {
+		return nil, err
+	}
+
+	event.Body = body
+	return event, nil
+}
+
+// ParseWebhookPayload parses a (verified) webhook message of the given event type (see HeaderEventType).
+// It returns WebhookParseError if the message is malformed or the event type is not supported.
+func ParseWebhookPayload(eventType string, body []byte) (*WebhookEvent, error) {
+	event := &WebhookEvent{Type: eventType}
+
+	switch eventType {
+	case EventCreatePledge, EventUpdatePledge, EventDeletePledge:
+		event.Pledge = &WebhookPledge{}
+		if err := json.Unmarshal(body, event.Pledge); err != nil {
+			return nil, WebhookParseError{Err: err}
+		}
+	default:
+		return nil, WebhookParseError{Err: fmt.Errorf("unsupported event type '%s'", eventType)}
+	}
+
+	return event, nil
+}
+
+// VerifyWebhookSignature verifies the sender of the message.
+// The signature is a hex encoded HMAC-MD5 of the message body keyed with the webhook secret, it's compared in constant time.
+// An empty or malformed signature never matches.
+func VerifyWebhookSignature(body []byte, signature string, secret string) bool {
+	if signature == "" {
+		return false
+	}
+
+	actual, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+
+	hash := hmac.New(md5.New, []byte(secret))
+	hash.Write(body)
+
+	return hmac.Equal(hash.Sum(nil), actual)
+}
+
+// VerifySignature verifies the sender of the message.
+//
+// Deprecated: use VerifyWebhookSignature instead.
+func VerifySignature(message []byte, secret string, signature string) (bool, error) {
+	return VerifyWebhookSignature(message, signature, secret), nil
 }
@@ -1,6 +1,9 @@
 package patreon
 
 import (
+	"errors"
+	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -20,3 +23,94 @@ func TestVerifySignature(t *testing.T) {
 	require.NoError(t, err)
 	require.False(t, result)
 }
+
+func TestVerifyWebhookSignature(t *testing.T) {
+	require.True(t, VerifyWebhookSignature([]byte(pledgeCreateMessage), "d339d4fa026a468919188cde6128b507", webhookSecret))
+	require.False(t, VerifyWebhookSignature([]byte(pledgeCreateMessage), "d339d4fa026a468919188cde6128b508", webhookSecret))
+	require.False(t, VerifyWebhookSignature([]byte(pledgeCreateMessage), "d339d4fa026a468919188cde6128b507", "secret"))
+	require.False(t, VerifyWebhookSignature([]byte(pledgeCreateMessage), "d339d4fa026a468919188cde6128b507-", webhookSecret))
+	require.False(t, VerifyWebhookSignature([]byte(pledgeCreateMessage), "", webhookSecret))
+}
+
+func newWebhookRequest(event, signature, body string) *http.Request {
+	r, _ := http.NewRequest("POST", "/webhook", strings.NewReader(body))
+	r.Header.Set(HeaderEventType, event)
+	r.Header.Set(HeaderSignature, signature)
+	return r
+}
+
+func TestReceiveWebhook(t *testing.T) {
+	r := newWebhookRequest(EventCreatePledge, "d339d4fa026a468919188cde6128b507", pledgeCreateMessage)
+
+	event, err := ReceiveWebhook(r, webhookSecret)
+	require.NoError(t, err)
+	require.Equal(t, EventCreatePledge, event.Type)
+	require.NotNil(t, event.Pledge)
+	require.Equal(t, "1", event.Pledge.Data.ID)
+	require.Equal(t, 150, event.Pledge.Data.Attributes.AmountCents)
+	require.Equal(t, "4221587", event.Pledge.Data.Relationships.Patron.Data.ID)
+	require.Len(t, event.Pledge.Included.Items, 11)
+	require.Equal(t, []byte(pledgeCreateMessage), event.Body)
+}
+
+func TestParseWebhookPayload(t *testing.T) {
+	event, err := ParseWebhookPayload(EventUpdatePledge, []byte(pledgeCreateMessage))
+	require.NoError(t, err)
+	require.Equal(t, EventUpdatePledge, event.Type)
+	require.Nil(t, event.Body)
+
+	patron := event.Pledge.Patron()
+	require.NotNil(t, patron)
+	require.Equal(t, "4221587", patron.ID)
+	require.Equal(t, "gdevs", patron.Attributes.Vanity)
+
+	reward := event.Pledge.Reward()
+	require.NotNil(t, reward)
+	require.Equal(t, "1146941", reward.ID)
+	require.Equal(t, "Early Access", reward.Attributes.Title)
+
+	_, err = ParseWebhookPayload(EventCreatePledge, []byte("{"))
+	require.Error(t, err)
+
+	_, ok := err.(WebhookParseError)
+	require.True(t, ok)
+}
+
+func TestReceiveWebhookInvalidSignature(t *testing.T) {
+	r := newWebhookRequest(EventCreatePledge, "d339d4fa026a468919188cde6128b507-", pledgeCreateMessage)
+
+	_, err := ReceiveWebhook(r, webhookSecret)
+	require.Equal(t, ErrInvalidSignature, err)
+}
+
+func TestReceiveWebhookUnsupportedEvent(t *testing.T) {
+	r := newWebhookRequest("members:create", "d339d4fa026a468919188cde6128b507", pledgeCreateMessage)
+
+	_, err := ReceiveWebhook(r, webhookSecret)
+	require.Error(t, err)
+	require.Equal(t, "failed to parse webhook: unsupported event type 'members:create'", err.Error())
+
+	_, ok := err.(WebhookParseError)
+	require.True(t, ok)
+}
+
+type failingReader struct{}
+
+func (failingReader) Read(p []byte) (int, error) {
+	return 0, errors.New("connection reset")
+}
+
+func TestReceiveWebhookReadError(t *testing.T) {
+	r, err := http.NewRequest("POST", "/webhook", failingReader{})
+	require.NoError(t, err)
+
+	_, err = ReceiveWebhook(r, webhookSecret)
+	require.Error(t, err)
+
+	_, ok := err.(WebhookReadError)
+	require.True(t, ok)
+}
+
+func TestAllWebhookEvents(t *testing.T) {
+	require.Equal(t, []string{"pledges:create", "pledges:update", "pledges:delete"}, AllWebhookEvents())
+}